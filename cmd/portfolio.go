@@ -22,6 +22,7 @@ import (
 	"github.com/Gituser143/cryptgo/pkg/api"
 	"github.com/Gituser143/cryptgo/pkg/display/portfolio"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -38,19 +39,35 @@ var portfolioCmd = &cobra.Command{
 		// Flag to determine if data must be sent when viewing per coin prices
 		sendData := true
 
+		// Currency prices are fetched in, set by the UI
+		vsCurrency := api.NewCurrency()
+
+		// Coins are fetched whatever their category
+		category := api.NewCategory()
+
 		// Fetch Coin Assets
 		eg.Go(func() error {
-			return api.GetAssets(ctx, dataChannel, &sendData)
+			return api.GetAssets(ctx, viper.GetInt("coins"), vsCurrency, category, dataChannel, &sendData)
+		})
+
+		// IDs of coins, looked up by the UI
+		coinIDMap := api.NewCoinIDMap()
+		coinIDMap.Load(ctx)
+
+		// Stream live prices of coins shown
+		priceHub := api.NewPriceHub()
+		eg.Go(func() error {
+			return priceHub.Run(ctx)
 		})
 
 		// Display UI for portfolio
 		eg.Go(func() error {
-			return portfolio.DisplayPortfolio(ctx, dataChannel, &sendData)
+			return portfolio.DisplayPortfolio(ctx, dataChannel, vsCurrency, coinIDMap, priceHub, &sendData)
 		})
 
 		if err := eg.Wait(); err != nil {
 			if err.Error() != "UI Closed" {
-				return err
+				return unwrapAPIError(err)
 			}
 		}
 		return nil
@@ -18,8 +18,11 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -27,39 +30,78 @@ import (
 
 	"github.com/Gituser143/cryptgo/pkg/api"
 	"github.com/Gituser143/cryptgo/pkg/display/allcoin"
+	"github.com/Gituser143/cryptgo/pkg/utils"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 )
 
-var cfgFile string
+var (
+	cfgFile     string
+	recordDir   string
+	replayDir   string
+	historyFrom string
+	historyTo   string
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "cryptgo",
 	Short: "A terminal application to watch crypto prices!",
 	Long:  `Crytpgo is a TUI based application written purely in Go to monitor and observe cryptocurrency prices in real time!`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The top 3 coins are always needed for their graphs
+		if viper.GetInt("coins") < 3 {
+			return fmt.Errorf("coins must be at least 3")
+		}
+		if err := initHistory(); err != nil {
+			return err
+		}
+		return initAPI()
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		// Context and errgroup used to manage routines
 		eg, ctx := errgroup.WithContext(context.Background())
 		dataChannel := make(chan api.AssetData)
+		trendingChannel := make(chan api.TrendingData)
 
 		// Flag to determine if data must be sent when viewing per coin prices
 		sendData := true
 
+		// Currency prices are fetched in, set by the UI
+		vsCurrency := api.NewCurrency()
+
+		// Category of coins shown, set by the UI
+		category := api.NewCategory()
+
 		// Fetch Coin Assets
 		eg.Go(func() error {
-			return api.GetAssets(ctx, dataChannel, &sendData)
+			return api.GetAssets(ctx, viper.GetInt("coins"), vsCurrency, category, dataChannel, &sendData)
+		})
+
+		// Fetch Trending Coins
+		eg.Go(func() error {
+			return api.GetTrending(ctx, trendingChannel, &sendData)
+		})
+
+		// IDs of coins, looked up by the UI
+		coinIDMap := api.NewCoinIDMap()
+		coinIDMap.Load(ctx)
+
+		// Stream live prices of coins shown
+		priceHub := api.NewPriceHub()
+		eg.Go(func() error {
+			return priceHub.Run(ctx)
 		})
 
 		// Display UI for overall coins
 		eg.Go(func() error {
-			return allcoin.DisplayAllCoins(ctx, dataChannel, &sendData)
+			return allcoin.DisplayAllCoins(ctx, dataChannel, trendingChannel, vsCurrency, category, coinIDMap, priceHub, &sendData)
 		})
 
 		if err := eg.Wait(); err != nil {
 			if err.Error() != "UI Closed" {
-				return err
+				return unwrapAPIError(err)
 			}
 		}
 		return nil
@@ -69,7 +111,11 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	err := rootCmd.Execute()
+
+	// Save recorded traffic, also when exiting on an error
+	cobra.CheckErr(api.StopRecording())
+	cobra.CheckErr(err)
 }
 
 func init() {
@@ -80,6 +126,44 @@ func init() {
 	// will be global for your application.
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cryptgo.yaml)")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "record all API traffic to the given directory")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "replay API traffic recorded in the given directory")
+	rootCmd.PersistentFlags().StringVar(&historyFrom, "from", "", "start date of coin history shown, such as 2021-05-01")
+	rootCmd.PersistentFlags().StringVar(&historyTo, "to", "", "end date of coin history shown (default is now)")
+	rootCmd.PersistentFlags().Int("coins", 250, "number of top coins, by market cap, to fetch")
+	cobra.CheckErr(viper.BindPFlag("coins", rootCmd.PersistentFlags().Lookup("coins")))
+
+	// API and HTTP client settings, these can also be set through the config file
+	defaults := api.DefaultClientConfig()
+	rootCmd.PersistentFlags().String("provider", "coingecko", "market data provider")
+	rootCmd.PersistentFlags().String("coingecko-url", defaults.CoinGeckoURL, "base URL of the CoinGecko API")
+	rootCmd.PersistentFlags().String("coincap-url", defaults.CoinCapURL, "base URL of the CoinCap API")
+	rootCmd.PersistentFlags().String("coincap-ws-url", defaults.CoinCapWSURL, "base URL of the CoinCap websocket API")
+	rootCmd.PersistentFlags().Duration("timeout", defaults.Timeout, "timeout for API requests")
+	rootCmd.PersistentFlags().String("proxy", "", "HTTP(S) proxy URL (default is read from the environment)")
+	rootCmd.PersistentFlags().String("ca-bundle", "", "PEM file of additional trusted CA certificates")
+	rootCmd.PersistentFlags().String("user-agent", defaults.UserAgent, "User-Agent sent with API requests")
+	rootCmd.PersistentFlags().Int("requests-per-minute", defaults.RequestsPerMinute, "API requests allowed per minute, 0 for unlimited")
+
+	configFlags := map[string]string{
+		"provider":                 "provider",
+		"api.coingecko_url":        "coingecko-url",
+		"api.coincap_url":          "coincap-url",
+		"api.coincap_ws_url":       "coincap-ws-url",
+		"http.timeout":             "timeout",
+		"http.proxy":               "proxy",
+		"http.ca_bundle":           "ca-bundle",
+		"http.user_agent":          "user-agent",
+		"http.requests_per_minute": "requests-per-minute",
+	}
+	for key, flag := range configFlags {
+		cobra.CheckErr(viper.BindPFlag(key, rootCmd.PersistentFlags().Lookup(flag)))
+	}
+
+	// CoinGecko API keys are only read from the config file or environment
+	cobra.CheckErr(viper.BindEnv("api.coingecko_key", "COINGECKO_API_KEY"))
+	cobra.CheckErr(viper.BindEnv("api.coingecko_plan", "COINGECKO_API_PLAN"))
+	viper.SetDefault("api.coingecko_plan", defaults.CoinGeckoPlan)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -97,6 +181,7 @@ func initConfig() {
 		viper.SetConfigName(".cryptgo")
 	}
 
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
@@ -104,3 +189,83 @@ func initConfig() {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
+
+// initAPI selects the market data provider, configures the shared HTTP client
+// and sets up recording or replaying of API traffic
+func initAPI() error {
+	if err := api.UseProvider(viper.GetString("provider")); err != nil {
+		return err
+	}
+
+	err := api.Configure(api.ClientConfig{
+		CoinGeckoURL: viper.GetString("api.coingecko_url"),
+		CoinCapURL:   viper.GetString("api.coincap_url"),
+		CoinCapWSURL: viper.GetString("api.coincap_ws_url"),
+		Timeout:      viper.GetDuration("http.timeout"),
+		Proxy:        viper.GetString("http.proxy"),
+		CABundle:     viper.GetString("http.ca_bundle"),
+		UserAgent:    viper.GetString("http.user_agent"),
+
+		RequestsPerMinute: viper.GetInt("http.requests_per_minute"),
+
+		CoinGeckoAPIKey: viper.GetString("api.coingecko_key"),
+		CoinGeckoPlan:   viper.GetString("api.coingecko_plan"),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Record or replay API traffic
+	switch {
+	case recordDir != "" && replayDir != "":
+		return fmt.Errorf("--record and --replay cannot be used together")
+	case recordDir != "":
+		return api.Record(recordDir)
+	case replayDir != "":
+		return api.Replay(replayDir)
+	}
+
+	return nil
+}
+
+// initHistory sets the range of history first shown for coins, if one is
+// given
+func initHistory() error {
+	if historyFrom == "" {
+		if historyTo != "" {
+			return fmt.Errorf("--to cannot be used without --from")
+		}
+		return nil
+	}
+
+	from, err := utils.ParseDate(historyFrom)
+	if err != nil {
+		return fmt.Errorf("--from: %v", err)
+	}
+
+	// History up to now is served if no end is given
+	to := time.Time{}
+	if historyTo != "" {
+		to, err = utils.ParseDate(historyTo)
+		if err != nil {
+			return fmt.Errorf("--to: %v", err)
+		}
+	}
+
+	if !to.IsZero() && !to.After(from) {
+		return fmt.Errorf("--to must be after --from")
+	}
+
+	api.SetDefaultHistory(api.HistoryOptions{From: from, To: to})
+	return nil
+}
+
+// unwrapAPIError strips request details from errors returned by APIs so that
+// they are reported clearly
+func unwrapAPIError(err error) error {
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// activityHistoryFile is the cache file activity of coins is recorded in
+	activityHistoryFile = "activity-history.json"
+
+	// activityInterval is the least time between recorded samples of a coin
+	activityInterval = time.Hour
+
+	// activityTrendPeriod is how far back trends of activity are measured
+	activityTrendPeriod = 24 * time.Hour
+
+	// activityRetention is how long coins not looked at are kept
+	activityRetention = 30 * 24 * time.Hour
+)
+
+var (
+	activityMutex   sync.Mutex
+	activityHistory map[string][]ActivitySample // samples by coin ID, oldest first
+)
+
+// recordActivity adds a sample of the activity of the coin specified by id
+// to the activity history, which is kept on disk as statistics change about
+// once a day. Samples of a coin are recorded at most once per
+// activityInterval. It returns the latest sample at least activityTrendPeriod
+// older than sample, which has a zero Time if there is none.
+func recordActivity(id string, sample ActivitySample) ActivitySample {
+	activityMutex.Lock()
+	defer activityMutex.Unlock()
+
+	if activityHistory == nil {
+		activityHistory = make(map[string][]ActivitySample)
+		readCache(activityHistoryFile, &activityHistory)
+	}
+
+	samples := activityHistory[id]
+	n := len(samples)
+	if n == 0 || sample.Time.Sub(samples[n-1].Time) >= activityInterval {
+		samples = append(samples, sample)
+
+		// Drop samples older than the one trends are measured from
+		start := 0
+		for start+1 < len(samples) && sample.Time.Sub(samples[start+1].Time) >= activityTrendPeriod {
+			start++
+		}
+		activityHistory[id] = samples[start:]
+
+		// Drop coins not looked at for a while
+		for coin, coinSamples := range activityHistory {
+			if sample.Time.Sub(coinSamples[len(coinSamples)-1].Time) > activityRetention {
+				delete(activityHistory, coin)
+			}
+		}
+
+		writeCache(activityHistoryFile, activityHistory)
+	}
+
+	samples = activityHistory[id]
+	if sample.Time.Sub(samples[0].Time) >= activityTrendPeriod {
+		return samples[0]
+	}
+
+	return ActivitySample{}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// useCacheDir caches data in a temporary directory until the test ends
+func useCacheDir(t *testing.T) {
+	saved, ok := os.LookupEnv("XDG_CACHE_HOME")
+	os.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv("XDG_CACHE_HOME", saved)
+		} else {
+			os.Unsetenv("XDG_CACHE_HOME")
+		}
+	})
+}
+
+func TestRecordActivity(t *testing.T) {
+	useCacheDir(t)
+	t.Cleanup(func() {
+		activityHistory = nil
+	})
+
+	start := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	sample := func(hours float64, stars int) ActivitySample {
+		return ActivitySample{
+			Time:     start.Add(time.Duration(hours * float64(time.Hour))),
+			Activity: Activity{GitHubStars: stars},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		sample ActivitySample
+		want   ActivitySample
+	}{
+		{"first sample", "bitcoin", sample(0, 100), ActivitySample{}},
+		{"within a day", "bitcoin", sample(12, 110), ActivitySample{}},
+		{"a day later", "bitcoin", sample(24, 120), sample(0, 100)},
+		{"not recorded within an hour", "bitcoin", sample(24.5, 125), sample(0, 100)},
+		{"older samples dropped", "bitcoin", sample(36, 130), sample(12, 110)},
+		{"other coins apart", "ethereum", sample(36, 50), ActivitySample{}},
+		{"coins not looked at dropped", "ethereum", sample(24*40, 60), sample(36, 50)},
+	}
+
+	for _, tt := range tests {
+		got := recordActivity(tt.id, tt.sample)
+		if !got.Time.Equal(tt.want.Time) || got.Activity != tt.want.Activity {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, ok := activityHistory["bitcoin"]; ok {
+		t.Error("coin not looked at for over a month is still recorded")
+	}
+
+	// Samples are kept across runs
+	activityHistory = nil
+	if got := recordActivity("ethereum", sample(24*41, 70)); !got.Time.Equal(sample(24*40, 60).Time) {
+		t.Errorf("after reading the cache: got %+v, want the sample of a day earlier", got)
+	}
+}
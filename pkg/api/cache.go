@@ -0,0 +1,116 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheDir returns the directory API data is cached in
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "cryptgo"), nil
+}
+
+// cacheVersion is stored with cached data and bumped whenever the format of
+// any cached data changes, so data cached by other versions is ignored
+const cacheVersion = 1
+
+// cacheEntry is the format of cache files
+type cacheEntry struct {
+	Version int             `json:"version"`
+	Written time.Time       `json:"written"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// readCache decodes the cache file name into v, however old it is. It
+// returns the time the data was cached and whether v was read.
+func readCache(name string, v interface{}) (time.Time, bool) {
+	dir, err := cacheDir()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	entry := cacheEntry{}
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Version != cacheVersion {
+		return time.Time{}, false
+	}
+
+	if err := json.Unmarshal(entry.Data, v); err != nil {
+		return time.Time{}, false
+	}
+
+	return entry.Written, true
+}
+
+// writeCache encodes v into the cache file name. The file is replaced at once
+// so readers never see it partly written. Cached data only saves requests,
+// so callers may ignore the error: data not cached is fetched again next
+// time.
+func writeCache(name string, v interface{}) error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cacheEntry{
+		Version: cacheVersion,
+		Written: time.Now(),
+		Data:    value,
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, name+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
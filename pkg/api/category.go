@@ -0,0 +1,92 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// categoriesFile is the cache file of categories
+	categoriesFile = "categories.json"
+
+	// categoriesMaxAge is how long cached categories are shown before
+	// refreshing them
+	categoriesMaxAge = 5 * time.Minute
+)
+
+// Category holds the category of coins served on the main page. The page
+// sets it when a category is selected and GetAssets reads it on every fetch.
+// Data fetched is tagged with the category it was fetched for.
+type Category struct {
+	mutex sync.RWMutex
+	id    string
+}
+
+// NewCategory returns a Category serving all coins
+func NewCategory() *Category {
+	return &Category{}
+}
+
+// Set selects the category with the given ID, an empty ID selects all coins
+func (c *Category) Set(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.id = id
+}
+
+// ID returns the ID of the category selected, which is empty for all coins
+func (c *Category) ID() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.id
+}
+
+// GetCategories serves the categories coins can be browsed by on
+// dataChannel, once. Categories cached within categoriesMaxAge are served
+// without fetching them.
+func GetCategories(ctx context.Context, dataChannel chan CategoryData) error {
+	data := CategoryData{}
+
+	written, ok := readCache(categoriesFile, &data.Categories)
+	if !ok || time.Since(written) > categoriesMaxAge {
+		var categories []CategoryMarket
+		err := unsupported("categories")
+		if provider, ok := CurrentProvider().(CategoriesProvider); ok {
+			categories, err = provider.Categories(ctx)
+		}
+		if err != nil {
+			data = CategoryData{Err: err}
+		} else {
+			data.Categories = categories
+
+			writeCache(categoriesFile, categories)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case dataChannel <- data:
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultCoinGeckoURL is the base URL of the CoinGecko API
+	DefaultCoinGeckoURL = "https://api.coingecko.com/api/v3"
+	// CoinGeckoProURL is the base URL of the CoinGecko API for pro plans
+	CoinGeckoProURL = "https://pro-api.coingecko.com/api/v3"
+	// DefaultCoinCapURL is the base URL of the CoinCap API
+	DefaultCoinCapURL = "https://api.coincap.io/v2"
+	// DefaultCoinCapWSURL is the base URL of the CoinCap websocket API
+	DefaultCoinCapWSURL = "wss://ws.coincap.io"
+)
+
+// ClientConfig holds settings of the HTTP client shared by all API requests
+type ClientConfig struct {
+	CoinGeckoURL string
+	CoinCapURL   string
+	CoinCapWSURL string
+	Timeout      time.Duration
+	Proxy        string // Proxy URL, if empty proxies are read from the environment
+	CABundle     string // Path to PEM encoded certificates trusted in addition to system ones
+	UserAgent    string
+
+	// RequestsPerMinute limits requests made by all pollers together, it is
+	// unlimited if 0
+	RequestsPerMinute int
+
+	// CoinGeckoAPIKey is sent with CoinGecko requests if set. CoinGeckoPlan
+	// is either "demo" or "pro", pro keys use the pro base URL unless
+	// CoinGeckoURL is changed from its default
+	CoinGeckoAPIKey string
+	CoinGeckoPlan   string
+}
+
+// DefaultClientConfig returns the ClientConfig used when none is set
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		CoinGeckoURL:      DefaultCoinGeckoURL,
+		CoinCapURL:        DefaultCoinCapURL,
+		CoinCapWSURL:      DefaultCoinCapWSURL,
+		Timeout:           30 * time.Second,
+		UserAgent:         "cryptgo",
+		RequestsPerMinute: 30,
+		CoinGeckoPlan:     "demo",
+	}
+}
+
+var (
+	configMutex sync.RWMutex
+
+	// clientConfig holds current client settings
+	clientConfig = DefaultClientConfig()
+
+	// netTransport sends requests over the network
+	netTransport http.RoundTripper = http.DefaultTransport
+
+	// wsDialer opens websocket connections over the network
+	wsDialer = websocket.DefaultDialer
+
+	// httpClient is shared by all API requests
+	httpClient = &http.Client{
+		Transport: apiTransport{},
+		Timeout:   clientConfig.Timeout,
+	}
+)
+
+// HTTPClient returns the HTTP client shared by all API requests. Providers
+// should use it so that their traffic honours client settings and can be
+// recorded and replayed.
+func HTTPClient() *http.Client {
+	return httpClient
+}
+
+// Config returns the current client settings
+func Config() ClientConfig {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+
+	return clientConfig
+}
+
+// Configure applies config to the shared HTTP client and websocket dialer.
+// Empty fields are set to their default values. It is meant to be called once,
+// before any requests are made.
+func Configure(config ClientConfig) error {
+	defaults := DefaultClientConfig()
+	if config.CoinGeckoURL == "" {
+		config.CoinGeckoURL = defaults.CoinGeckoURL
+	}
+	if config.CoinCapURL == "" {
+		config.CoinCapURL = defaults.CoinCapURL
+	}
+	if config.CoinCapWSURL == "" {
+		config.CoinCapWSURL = defaults.CoinCapWSURL
+	}
+	config.CoinGeckoURL = strings.TrimSuffix(config.CoinGeckoURL, "/")
+
+	// Set CoinGecko plan
+	config.CoinGeckoPlan = strings.ToLower(config.CoinGeckoPlan)
+	switch config.CoinGeckoPlan {
+	case "":
+		config.CoinGeckoPlan = defaults.CoinGeckoPlan
+	case "demo":
+	case "pro":
+		if config.CoinGeckoURL == DefaultCoinGeckoURL {
+			config.CoinGeckoURL = CoinGeckoProURL
+		}
+	default:
+		return fmt.Errorf("unknown CoinGecko plan %q, expected demo or pro", config.CoinGeckoPlan)
+	}
+
+	config.CoinCapURL = strings.TrimSuffix(config.CoinCapURL, "/")
+	config.CoinCapWSURL = strings.TrimSuffix(config.CoinCapWSURL, "/")
+
+	// Set proxy
+	proxy := http.ProxyFromEnvironment
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %v", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	// Set trusted certificates
+	tlsConfig := &tls.Config{}
+	if config.CABundle != "" {
+		pem, err := ioutil.ReadFile(config.CABundle)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %v", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle %s", config.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	t := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	d := &websocket.Dialer{
+		Proxy:            proxy,
+		TLSClientConfig:  tlsConfig,
+		HandshakeTimeout: config.Timeout,
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	clientConfig = config
+	netTransport = t
+	wsDialer = d
+	httpClient.Timeout = config.Timeout
+	budget.SetRate(config.RequestsPerMinute)
+
+	return nil
+}
+
+// networkTransport sends requests through the configured network transport
+type networkTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (networkTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	configMutex.RLock()
+	t := netTransport
+	configMutex.RUnlock()
+
+	return t.RoundTrip(req)
+}
+
+// apiTransport points requests at the configured base URLs and sets the
+// User-Agent and API key headers before handing them to the traffic transport
+type apiTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (apiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	config := Config()
+
+	// Requests must not be modified, so work on a copy
+	req = req.Clone(req.Context())
+
+	rawURL := req.URL.String()
+	rewrittenURL := rewriteBaseURL(rawURL, DefaultCoinGeckoURL, config.CoinGeckoURL)
+	rewrittenURL = rewriteBaseURL(rewrittenURL, DefaultCoinCapURL, config.CoinCapURL)
+
+	if rewrittenURL != rawURL {
+		u, err := url.Parse(rewrittenURL)
+		if err != nil {
+			return nil, err
+		}
+		req.URL = u
+		req.Host = u.Host
+	}
+
+	if config.UserAgent != "" {
+		req.Header.Set("User-Agent", config.UserAgent)
+	}
+
+	isGecko := strings.HasPrefix(req.URL.String(), config.CoinGeckoURL)
+	if isGecko && config.CoinGeckoAPIKey != "" {
+		req.Header.Set(fmt.Sprintf("x-cg-%s-api-key", config.CoinGeckoPlan), config.CoinGeckoAPIKey)
+	}
+
+	// Wait for the shared request budget, unless replaying recorded traffic
+	// which costs no requests
+	replay := replaying()
+	if !replay {
+		if err := budget.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := trafficTransport{}.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 400 {
+		return res, nil
+	}
+
+	// Report error responses clearly instead of passing the body on
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+
+	apiErr := &APIError{
+		API:        "CoinCap",
+		StatusCode: res.StatusCode,
+		Message:    errorMessage(body),
+		RetryAfter: parseRetryAfter(res.Header.Get("Retry-After")),
+	}
+
+	if isGecko {
+		apiErr.API = "CoinGecko"
+		if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+			if config.CoinGeckoAPIKey == "" {
+				apiErr.Message += ", set an API key with api.coingecko_key or COINGECKO_API_KEY"
+			} else {
+				apiErr.Message += fmt.Sprintf(", check that the API key is valid for the %s plan (api.coingecko_plan)", config.CoinGeckoPlan)
+			}
+		}
+	} else if !strings.HasPrefix(req.URL.String(), config.CoinCapURL) {
+		apiErr.API = req.URL.Host
+	}
+
+	// Pause all requests when rate limited
+	if res.StatusCode == http.StatusTooManyRequests && !replay {
+		pause := apiErr.RetryAfter
+		if pause == 0 {
+			pause = time.Minute
+		}
+		budget.Pause(pause)
+	}
+
+	return nil, apiErr
+}
+
+// rewriteBaseURL replaces the base URL from of rawURL with to
+func rewriteBaseURL(rawURL, from, to string) string {
+	if from == to || !strings.HasPrefix(rawURL, from) {
+		return rawURL
+	}
+	return to + strings.TrimPrefix(rawURL, from)
+}
+
+func defaultDialWebsocket(ctx context.Context, url string) (wsConn, error) {
+	config := Config()
+
+	configMutex.RLock()
+	d := wsDialer
+	configMutex.RUnlock()
+
+	header := http.Header{}
+	if config.UserAgent != "" {
+		header.Set("User-Agent", config.UserAgent)
+	}
+
+	c, _, err := d.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,232 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// configureClient applies config until the test ends
+func configureClient(t *testing.T, config ClientConfig) {
+	t.Helper()
+
+	if err := Configure(config); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		Configure(DefaultClientConfig())
+		budget.mutex.Lock()
+		budget.pausedUntil = time.Time{}
+		budget.mutex.Unlock()
+	})
+}
+
+// request holds what a test server received
+type request struct {
+	path      string
+	userAgent string
+	apiKey    string
+}
+
+// newServer returns a test server recording requests to it, which responds
+// to paths with the status codes in statuses and with 200 otherwise
+func newServer(t *testing.T, statuses map[string]int, requests chan<- request) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- request{
+			path:      r.URL.Path,
+			userAgent: r.Header.Get("User-Agent"),
+			apiKey:    r.Header.Get("x-cg-pro-api-key"),
+		}
+
+		status, ok := statuses[r.URL.Path]
+		if !ok {
+			w.Write([]byte(`{}`))
+			return
+		}
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "120")
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"status": {"error_code": 1, "error_message": "rejected"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClientRequests(t *testing.T) {
+	requests := make(chan request, 1)
+	statuses := map[string]int{
+		"/unauthorized": http.StatusUnauthorized,
+		"/limited":      http.StatusTooManyRequests,
+	}
+	gecko := newServer(t, statuses, requests)
+	coinCap := newServer(t, statuses, requests)
+
+	configureClient(t, ClientConfig{
+		CoinGeckoURL:    gecko.URL + "/",
+		CoinCapURL:      coinCap.URL,
+		UserAgent:       "cryptgo-test",
+		CoinGeckoAPIKey: "secret",
+		CoinGeckoPlan:   "Pro",
+	})
+
+	tests := []struct {
+		url    string
+		want   request
+		status int    // status of the APIError returned, 0 if none
+		api    string // API named by the error
+		hint   string // text added to the error message
+	}{
+		{
+			url:  DefaultCoinGeckoURL + "/ping",
+			want: request{"/ping", "cryptgo-test", "secret"},
+		},
+		{
+			url:  DefaultCoinCapURL + "/assets",
+			want: request{"/assets", "cryptgo-test", ""},
+		},
+		{
+			url:    DefaultCoinGeckoURL + "/unauthorized",
+			want:   request{"/unauthorized", "cryptgo-test", "secret"},
+			status: http.StatusUnauthorized,
+			api:    "CoinGecko",
+			hint:   "valid for the pro plan",
+		},
+		{
+			url:    DefaultCoinCapURL + "/unauthorized",
+			want:   request{"/unauthorized", "cryptgo-test", ""},
+			status: http.StatusUnauthorized,
+			api:    "CoinCap",
+		},
+		{
+			url:    DefaultCoinGeckoURL + "/limited",
+			want:   request{"/limited", "cryptgo-test", "secret"},
+			status: http.StatusTooManyRequests,
+			api:    "CoinGecko",
+		},
+	}
+
+	for _, tt := range tests {
+		res, err := HTTPClient().Get(tt.url)
+		if err == nil {
+			res.Body.Close()
+		}
+
+		if got := <-requests; got != tt.want {
+			t.Errorf("%s: server received %+v, want %+v", tt.url, got, tt.want)
+		}
+
+		if tt.status == 0 {
+			if err != nil {
+				t.Errorf("%s: %v", tt.url, err)
+			}
+			continue
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("%s: got error %v, want an APIError", tt.url, err)
+			continue
+		}
+		if apiErr.StatusCode != tt.status || apiErr.API != tt.api || !strings.HasPrefix(apiErr.Message, "rejected") {
+			t.Errorf("%s: got %+v, want status %d from %s", tt.url, apiErr, tt.status, tt.api)
+		}
+		if !strings.Contains(apiErr.Message, tt.hint) {
+			t.Errorf("%s: message %q does not contain %q", tt.url, apiErr.Message, tt.hint)
+		}
+	}
+
+	// Rate limits pause all requests for as long as asked
+	budget.mutex.Lock()
+	paused := time.Until(budget.pausedUntil)
+	budget.mutex.Unlock()
+	if paused < time.Minute || paused > 2*time.Minute {
+		t.Errorf("requests paused for %v after a rate limit, want 2 minutes", paused)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ClientConfig
+		check  func(ClientConfig) bool
+		err    string
+	}{
+		{
+			name:   "defaults",
+			config: ClientConfig{},
+			check: func(c ClientConfig) bool {
+				return c.CoinGeckoURL == DefaultCoinGeckoURL && c.CoinCapURL == DefaultCoinCapURL && c.CoinGeckoPlan == "demo"
+			},
+		},
+		{
+			name:   "pro plan uses the pro URL",
+			config: ClientConfig{CoinGeckoPlan: "pro"},
+			check:  func(c ClientConfig) bool { return c.CoinGeckoURL == CoinGeckoProURL },
+		},
+		{
+			name:   "pro plan keeps a changed URL",
+			config: ClientConfig{CoinGeckoPlan: "pro", CoinGeckoURL: "http://localhost:8080/"},
+			check:  func(c ClientConfig) bool { return c.CoinGeckoURL == "http://localhost:8080" },
+		},
+		{
+			name:   "unknown plan",
+			config: ClientConfig{CoinGeckoPlan: "gold"},
+			err:    `unknown CoinGecko plan "gold"`,
+		},
+		{
+			name:   "invalid proxy",
+			config: ClientConfig{Proxy: "http://[::1"},
+			err:    "invalid proxy URL",
+		},
+		{
+			name:   "missing CA bundle",
+			config: ClientConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")},
+			err:    "failed to read CA bundle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				Configure(DefaultClientConfig())
+			})
+
+			err := Configure(tt.config)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(Config()) {
+				t.Errorf("got config %+v", Config())
+			}
+		})
+	}
+}
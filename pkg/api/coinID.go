@@ -17,92 +17,161 @@ limitations under the License.
 package api
 
 import (
-	"encoding/json"
-	"net/http"
+	"context"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
-// NewCoinIDMap returns an instance of CoinIDMap
-func NewCoinIDMap() CoinIDMap {
-	c := make(CoinIDMap)
-	return c
-}
-
-// Populate updates values into the CoinIDMap
-func (c CoinIDMap) Populate() {
+// coinIDsFile is the cache file of coin IDs
+const coinIDsFile = "coin-ids.json"
 
-	var m sync.Mutex
-	var wg sync.WaitGroup
+// coinIDsMaxAge is how long cached coin IDs are used before refreshing them
+const coinIDsMaxAge = 24 * time.Hour
 
-	wg.Add(2)
-
-	// Get CoinCapIDs
-	go func(IDMap CoinIDMap, m *sync.Mutex, wg *sync.WaitGroup) {
-		defer wg.Done()
-		url := "https://api.coincap.io/v2/assets?limit=2000"
-		method := "GET"
+// CoinIDMap maps CoinGecko IDs of coins to their CoinID. Many coins share
+// a symbol, so coins are looked up by symbol through Candidates. It is safe
+// for concurrent use, so it can be refreshed in the background.
+type CoinIDMap struct {
+	mutex    sync.RWMutex
+	coins    map[string]CoinID
+	bySymbol map[string][]string // CoinGecko IDs by upper case symbol, best ranked first
+}
 
-		client := &http.Client{}
+// NewCoinIDMap returns an empty CoinIDMap
+func NewCoinIDMap() *CoinIDMap {
+	return &CoinIDMap{
+		coins:    make(map[string]CoinID),
+		bySymbol: make(map[string][]string),
+	}
+}
 
-		req, err := http.NewRequest(method, url, nil)
-		if err != nil {
+// Load fills the CoinIDMap from the cache at once, even if it has expired, so
+// the UI need not wait on the network and works offline. IDs which have
+// expired or are not cached are fetched in the background, and then
+// refreshed once they expire until the context is cancelled. Fetches which
+// fail are retried with backoff. Until IDs are fetched, Get knows coins only
+// by their CoinGecko ID.
+func (c *CoinIDMap) Load(ctx context.Context) {
+	coinIDs := []CoinID{}
+	written, ok := readCache(coinIDsFile, &coinIDs)
+	if ok {
+		c.Set(coinIDs)
+	}
+
+	delay := time.Duration(0)
+	if ok && time.Since(written) < coinIDsMaxAge {
+		delay = coinIDsMaxAge - time.Since(written)
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
 			return
+		case <-time.After(delay):
 		}
 
-		res, err := client.Do(req)
-		if err != nil {
-			return
+		poll(ctx, coinIDsMaxAge, func() error {
+			return c.Populate(ctx)
+		}, func(error) {})
+	}()
+}
+
+// Populate updates values into the CoinIDMap from the current provider and
+// caches them. Values are kept if they cannot be fetched. If only some can
+// be, they are used while the CoinIDMap is empty but are not cached, and the
+// error met is returned.
+func (c *CoinIDMap) Populate(ctx context.Context) error {
+	coinIDs, err := CurrentProvider().CoinIDs(ctx)
+	if err != nil {
+		if len(coinIDs) > 0 && c.empty() {
+			c.Set(coinIDs)
 		}
-		defer res.Body.Close()
+		return err
+	}
 
-		coinCapData := CoinCapData{}
+	c.Set(coinIDs)
 
-		err = json.NewDecoder(res.Body).Decode(&coinCapData)
-		if err != nil {
-			return
-		}
+	writeCache(coinIDsFile, coinIDs)
+	return nil
+}
 
-		for _, val := range coinCapData.Data {
-			m.Lock()
-			if _, ok := IDMap[val.Symbol]; ok {
-				IDMap[val.Symbol] = CoinID{
-					CoinCapID:   val.ID,
-					CoinGeckoID: IDMap[val.Symbol].CoinGeckoID,
-				}
-			} else {
-				IDMap[val.Symbol] = CoinID{
-					CoinCapID: val.ID,
-				}
-			}
-			m.Unlock()
-		}
-	}(c, &m, &wg)
+// empty reports whether the CoinIDMap holds no coins
+func (c *CoinIDMap) empty() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 
-	go func(IDMap CoinIDMap, m *sync.Mutex, wg *sync.WaitGroup) {
-		defer wg.Done()
+	return len(c.coins) == 0
+}
 
-		coinPtr, err := getTopNCoins(250)
-		if err != nil {
-			return
-		}
+// Set replaces the contents of the CoinIDMap with coinIDs
+func (c *CoinIDMap) Set(coinIDs []CoinID) {
+	coins := make(map[string]CoinID, len(coinIDs))
+	bySymbol := make(map[string][]string)
 
-		for _, val := range coinPtr {
-			symbol := strings.ToUpper(val.Symbol)
-			m.Lock()
-			if _, ok := IDMap[symbol]; ok {
-				IDMap[symbol] = CoinID{
-					CoinGeckoID: val.ID,
-					CoinCapID:   IDMap[symbol].CoinCapID,
-				}
-			} else {
-				IDMap[symbol] = CoinID{
-					CoinGeckoID: val.ID,
-				}
-			}
-			m.Unlock()
+	for _, coinID := range coinIDs {
+		if coinID.CoinGeckoID == "" {
+			continue
 		}
-	}(c, &m, &wg)
 
-	wg.Wait()
+		coinID.Symbol = strings.ToUpper(coinID.Symbol)
+		coins[coinID.CoinGeckoID] = coinID
+		bySymbol[coinID.Symbol] = append(bySymbol[coinID.Symbol], coinID.CoinGeckoID)
+	}
+
+	for _, ids := range bySymbol {
+		sort.Slice(ids, func(i, j int) bool {
+			return rankedBefore(coins[ids[i]], coins[ids[j]])
+		})
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.coins = coins
+	c.bySymbol = bySymbol
+}
+
+// Get returns the CoinID of the coin with the given CoinGecko ID, it is
+// empty apart from the CoinGeckoID if the coin is unknown
+func (c *CoinIDMap) Get(id string) CoinID {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if coinID, ok := c.coins[id]; ok {
+		return coinID
+	}
+
+	return CoinID{CoinGeckoID: id}
+}
+
+// Candidates returns the coins with the given symbol, best ranked first
+func (c *CoinIDMap) Candidates(symbol string) []CoinID {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	ids := c.bySymbol[strings.ToUpper(symbol)]
+
+	candidates := make([]CoinID, 0, len(ids))
+	for _, id := range ids {
+		candidates = append(candidates, c.coins[id])
+	}
+
+	return candidates
+}
+
+// rankedBefore reports whether coin a is ranked before coin b. Unranked
+// coins come last, ordered by name.
+func rankedBefore(a, b CoinID) bool {
+	switch {
+	case a.Rank == b.Rank:
+		return a.Name < b.Name
+	case a.Rank == 0:
+		return false
+	case b.Rank == 0:
+		return true
+	default:
+		return a.Rank < b.Rank
+	}
 }
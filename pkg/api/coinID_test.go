@@ -0,0 +1,67 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPopulate(t *testing.T) {
+	useCacheDir(t)
+	ctx := context.Background()
+
+	complete := []CoinID{{CoinGeckoID: "bitcoin", CoinCapID: "bitcoin"}, {CoinGeckoID: "ethereum"}}
+	partial := []CoinID{{CoinGeckoID: "bitcoin"}}
+	failed := errors.New("failed to fetch CoinCap IDs")
+
+	p := newFakeProvider(0)
+	useFakeProvider(t, p)
+	c := NewCoinIDMap()
+
+	// Partial IDs are used while none are known, but are not cached
+	p.coinIDs, p.coinIDsErr = partial, failed
+	if err := c.Populate(ctx); err != failed {
+		t.Errorf("Populate() = %v, want %v", err, failed)
+	}
+	if got := c.Get("bitcoin"); got != partial[0] {
+		t.Errorf("Get(bitcoin) = %+v, want %+v", got, partial[0])
+	}
+	if _, ok := readCache(coinIDsFile, &[]CoinID{}); ok {
+		t.Error("partial coin IDs were cached")
+	}
+
+	// Complete IDs replace them and are cached
+	p.coinIDs, p.coinIDsErr = complete, nil
+	if err := c.Populate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	cached := []CoinID{}
+	if _, ok := readCache(coinIDsFile, &cached); !ok || len(cached) != len(complete) {
+		t.Errorf("cached %+v, want %+v", cached, complete)
+	}
+
+	// Partial IDs do not replace known ones
+	p.coinIDs, p.coinIDsErr = partial, failed
+	if err := c.Populate(ctx); err != failed {
+		t.Errorf("Populate() = %v, want %v", err, failed)
+	}
+	if got := c.Get("bitcoin"); got != complete[0] {
+		t.Errorf("Get(bitcoin) = %+v, want %+v", got, complete[0])
+	}
+}
@@ -0,0 +1,1009 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+)
+
+// CoinGeckoProvider serves market data from CoinGecko. Live prices, currency
+// rates and CoinCap IDs are served from CoinCap.
+type CoinGeckoProvider struct {
+	httpClient *http.Client
+
+	// CoinCap IDs of coins, loaded once prices are first streamed
+	coinIDsOnce sync.Once
+	coinIDs     *CoinIDMap
+}
+
+// CoinGeckoProvider serves all data fetchers use
+var (
+	_ Provider                 = (*CoinGeckoProvider)(nil)
+	_ CategoriesProvider       = (*CoinGeckoProvider)(nil)
+	_ MarketChartRangeProvider = (*CoinGeckoProvider)(nil)
+	_ OHLCProvider             = (*CoinGeckoProvider)(nil)
+	_ TickersProvider          = (*CoinGeckoProvider)(nil)
+	_ SearchProvider           = (*CoinGeckoProvider)(nil)
+	_ TrendingProvider         = (*CoinGeckoProvider)(nil)
+	_ GlobalProvider           = (*CoinGeckoProvider)(nil)
+	_ LivePricesProvider       = (*CoinGeckoProvider)(nil)
+	_ VsCurrenciesProvider     = (*CoinGeckoProvider)(nil)
+	_ CurrencyRatesProvider    = (*CoinGeckoProvider)(nil)
+)
+
+// NewCoinGeckoProvider returns a pointer to an instance of CoinGeckoProvider
+// which uses the shared HTTP client
+func NewCoinGeckoProvider() *CoinGeckoProvider {
+	return &CoinGeckoProvider{
+		httpClient: HTTPClient(),
+	}
+}
+
+// getJSON sends a GET request to reqURL through the shared HTTP client and
+// decodes the JSON response into v. The request is abandoned once ctx is
+// cancelled.
+func (p *CoinGeckoProvider) getJSON(ctx context.Context, reqURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// Markets returns market data of coins ordered by market cap
+func (p *CoinGeckoProvider) Markets(ctx context.Context, vsCurrency string, ids []string, perPage, page int) ([]CoinMarket, error) {
+	params := url.Values{}
+	if len(ids) > 0 {
+		params.Set("ids", strings.Join(ids, ","))
+	}
+
+	return p.coinsMarkets(ctx, vsCurrency, params, perPage, page)
+}
+
+// CategoryMarkets returns market data of coins in a category, ordered by
+// market cap
+func (p *CoinGeckoProvider) CategoryMarkets(ctx context.Context, category, vsCurrency string, perPage, page int) ([]CoinMarket, error) {
+	params := url.Values{}
+	params.Set("category", category)
+
+	return p.coinsMarkets(ctx, vsCurrency, params, perPage, page)
+}
+
+// coinsMarkets returns a page of market data of coins selected by params,
+// ordered by market cap, with sparklines and price changes
+func (p *CoinGeckoProvider) coinsMarkets(ctx context.Context, vsCurrency string, params url.Values, perPage, page int) ([]CoinMarket, error) {
+	pcp := geckoTypes.PriceChangePercentageObject
+	priceChangePercentage := []string{pcp.PCP1h, pcp.PCP24h, pcp.PCP7d, pcp.PCP14d, pcp.PCP30d, pcp.PCP200d, pcp.PCP1y}
+
+	params.Set("vs_currency", vsCurrency)
+	params.Set("order", geckoTypes.OrderTypeObject.MarketCapDesc)
+	params.Set("per_page", strconv.Itoa(perPage))
+	params.Set("page", strconv.Itoa(page))
+	params.Set("sparkline", "true")
+	params.Set("price_change_percentage", strings.Join(priceChangePercentage, ","))
+	reqURL := fmt.Sprintf("%s/coins/markets?%s", Config().CoinGeckoURL, params.Encode())
+
+	data := []coinsMarketItem{}
+	if err := p.getJSON(ctx, reqURL, &data); err != nil {
+		return nil, err
+	}
+
+	coinData := make([]CoinMarket, 0, len(data))
+	for _, val := range data {
+		coinData = append(coinData, toCoinMarket(val))
+	}
+
+	return coinData, nil
+}
+
+// Categories returns categories of coins with their aggregate market data,
+// ordered by market cap
+func (p *CoinGeckoProvider) Categories(ctx context.Context) ([]CategoryMarket, error) {
+	reqURL := fmt.Sprintf("%s/coins/categories?order=market_cap_desc", Config().CoinGeckoURL)
+
+	categories := []CategoryMarket{}
+	if err := p.getJSON(ctx, reqURL, &categories); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// coinsMarketItem is market data of a coin as served by CoinGecko, with the
+// max supply go-gecko leaves out
+type coinsMarketItem struct {
+	geckoTypes.CoinsMarketItem
+	MaxSupply *float64 `json:"max_supply"`
+}
+
+// toCoinMarket converts a coinsMarketItem to a CoinMarket
+func toCoinMarket(val coinsMarketItem) CoinMarket {
+	changes := map[string]*float64{
+		"1h":   val.PriceChangePercentage1hInCurrency,
+		"24h":  val.PriceChangePercentage24hInCurrency,
+		"7d":   val.PriceChangePercentage7dInCurrency,
+		"14d":  val.PriceChangePercentage14dInCurrency,
+		"30d":  val.PriceChangePercentage30dInCurrency,
+		"200d": val.PriceChangePercentage200dInCurrency,
+		"1y":   val.PriceChangePercentage1yInCurrency,
+	}
+
+	priceChangePercentage := map[string]float64{
+		"24h": val.PriceChangePercentage24h,
+	}
+	for duration, change := range changes {
+		if change != nil {
+			priceChangePercentage[duration] = *change
+		}
+	}
+
+	sparkline := []float64{}
+	if val.SparklineIn7d != nil {
+		sparkline = val.SparklineIn7d.Price
+	}
+
+	maxSupply := 0.0
+	if val.MaxSupply != nil {
+		maxSupply = *val.MaxSupply
+	}
+
+	return CoinMarket{
+		ID:                    val.ID,
+		Symbol:                val.Symbol,
+		Name:                  val.Name,
+		MarketCapRank:         int(val.MarketCapRank),
+		CurrentPrice:          val.CurrentPrice,
+		MarketCap:             val.MarketCap,
+		TotalVolume:           val.TotalVolume,
+		High24:                val.High24,
+		Low24:                 val.Low24,
+		CirculatingSupply:     val.CirculatingSupply,
+		TotalSupply:           val.TotalSupply,
+		MaxSupply:             maxSupply,
+		Sparkline7d:           sparkline,
+		PriceChangePercentage: priceChangePercentage,
+	}
+}
+
+// MarketChart returns the price, market cap and volume history of a coin
+func (p *CoinGeckoProvider) MarketChart(ctx context.Context, id, vsCurrency, days string) (MarketChart, error) {
+	params := url.Values{}
+	params.Set("vs_currency", vsCurrency)
+	params.Set("days", days)
+	reqURL := fmt.Sprintf("%s/coins/%s/market_chart?%s", Config().CoinGeckoURL, url.PathEscape(id), params.Encode())
+
+	data := geckoTypes.CoinsIDMarketChart{}
+	if err := p.getJSON(ctx, reqURL, &data); err != nil {
+		return MarketChart{}, err
+	}
+
+	chartValues := func(items *[]geckoTypes.ChartItem) []float64 {
+		values := []float64{}
+		if items == nil {
+			return values
+		}
+		for _, v := range *items {
+			values = append(values, float64(v[1]))
+		}
+		return values
+	}
+
+	return MarketChart{
+		Prices:       chartValues(data.Prices),
+		MarketCaps:   chartValues(data.MarketCaps),
+		TotalVolumes: chartValues(data.TotalVolumes),
+	}, nil
+}
+
+// openRangeEnd is requested as the end of ranges open up to now, so their
+// requests do not change with the time they are made and can be replayed
+var openRangeEnd = time.Unix(math.MaxInt32, 0)
+
+// MarketChartRange returns the price, market cap and volume history of a
+// coin between from and to. CoinGecko serves hourly data for ranges up to 90
+// days and daily data for longer ones.
+func (p *CoinGeckoProvider) MarketChartRange(ctx context.Context, id, vsCurrency string, from, to time.Time) (MarketChart, error) {
+	params := url.Values{}
+	params.Set("vs_currency", vsCurrency)
+	params.Set("from", strconv.FormatInt(from.Unix(), 10))
+	if to.IsZero() {
+		to = openRangeEnd
+	}
+	params.Set("to", strconv.FormatInt(to.Unix(), 10))
+	reqURL := fmt.Sprintf("%s/coins/%s/market_chart/range?%s", Config().CoinGeckoURL, url.PathEscape(id), params.Encode())
+
+	// Values are served as [time (ms), value]
+	data := struct {
+		Prices       [][2]float64 `json:"prices"`
+		MarketCaps   [][2]float64 `json:"market_caps"`
+		TotalVolumes [][2]float64 `json:"total_volumes"`
+	}{}
+	if err := p.getJSON(ctx, reqURL, &data); err != nil {
+		return MarketChart{}, err
+	}
+
+	chartValues := func(items [][2]float64) []float64 {
+		values := make([]float64, len(items))
+		for i, v := range items {
+			values[i] = v[1]
+		}
+		return values
+	}
+
+	times := make([]time.Time, len(data.Prices))
+	for i, v := range data.Prices {
+		times[i] = time.Unix(0, int64(v[0])*int64(time.Millisecond))
+	}
+
+	return MarketChart{
+		Prices:       chartValues(data.Prices),
+		MarketCaps:   chartValues(data.MarketCaps),
+		TotalVolumes: chartValues(data.TotalVolumes),
+		Times:        times,
+	}, nil
+}
+
+// ohlcDays lists the numbers of days CoinGecko serves OHLC candles for,
+// besides "max"
+var ohlcDays = []int{1, 7, 14, 30, 90, 180, 365}
+
+// OHLC returns candles of a coin over the given number of days. CoinGecko
+// only serves candles for some numbers of days, so the next larger number
+// is fetched and older candles are dropped.
+func (p *CoinGeckoProvider) OHLC(ctx context.Context, id, vsCurrency, days string) ([]OHLC, error) {
+	fetchDays := "max"
+	n, err := strconv.Atoi(days)
+	if err == nil {
+		for _, d := range ohlcDays {
+			if d >= n {
+				fetchDays = strconv.Itoa(d)
+				break
+			}
+		}
+	}
+
+	params := url.Values{}
+	params.Set("vs_currency", vsCurrency)
+	params.Set("days", fetchDays)
+	reqURL := fmt.Sprintf("%s/coins/%s/ohlc?%s", Config().CoinGeckoURL, url.PathEscape(id), params.Encode())
+
+	// Candles are served as [time (ms), open, high, low, close]
+	data := [][5]float64{}
+	if err := p.getJSON(ctx, reqURL, &data); err != nil {
+		return nil, err
+	}
+
+	// Drop candles before the days asked for
+	start := time.Time{}
+	if fetchDays != days && n > 0 {
+		start = time.Now().AddDate(0, 0, -n)
+	}
+
+	candles := make([]OHLC, 0, len(data))
+	for _, val := range data {
+		t := time.Unix(0, int64(val[0])*int64(time.Millisecond))
+		if t.Before(start) {
+			continue
+		}
+
+		candles = append(candles, OHLC{
+			Time:  t,
+			Open:  val[1],
+			High:  val[2],
+			Low:   val[3],
+			Close: val[4],
+		})
+	}
+
+	return candles, nil
+}
+
+// geckoCoin is used to unmarshal details of a coin from the CoinGecko API,
+// along with the links and contract addresses go-gecko leaves out
+type geckoCoin struct {
+	geckoTypes.CoinsID
+	Links struct {
+		Homepage       []string `json:"homepage"`
+		Whitepaper     string   `json:"whitepaper"`
+		BlockchainSite []string `json:"blockchain_site"`
+		SubredditURL   string   `json:"subreddit_url"`
+		ReposURL       struct {
+			GitHub []string `json:"github"`
+		} `json:"repos_url"`
+	} `json:"links"`
+	Platforms map[string]string `json:"platforms"`
+}
+
+// CoinDetails returns details of the coin specified by id, priced in
+// vsCurrency
+func (p *CoinGeckoProvider) CoinDetails(ctx context.Context, id, vsCurrency string) (CoinDetails, error) {
+	// Set Parameters
+	params := url.Values{}
+	params.Set("localization", "false")
+	params.Set("tickers", "false")
+	params.Set("market_data", "true")
+	params.Set("community_data", "true")
+	params.Set("developer_data", "true")
+	params.Set("sparkline", "false")
+	reqURL := fmt.Sprintf("%s/coins/%s?%s", Config().CoinGeckoURL, url.PathEscape(id), params.Encode())
+
+	coinData := geckoCoin{}
+	if err := p.getJSON(ctx, reqURL, &coinData); err != nil {
+		return CoinDetails{}, err
+	}
+	if coinData.MarketData == nil {
+		return CoinDetails{}, fmt.Errorf("no market data for %s", id)
+	}
+
+	// Get Explorer links
+	explorerLinks := [][]string{}
+	for _, site := range coinData.Links.BlockchainSite {
+		if site != "" {
+			explorerLinks = append(explorerLinks, []string{site})
+		}
+	}
+
+	// Get homepage, repositories and contract addresses, skipping blanks
+	website := ""
+	for _, page := range coinData.Links.Homepage {
+		if page != "" {
+			website = page
+			break
+		}
+	}
+
+	repos := []string{}
+	for _, repo := range coinData.Links.ReposURL.GitHub {
+		if repo != "" {
+			repos = append(repos, repo)
+		}
+	}
+
+	contracts := map[string]string{}
+	for platform, address := range coinData.Platforms {
+		if platform != "" && address != "" {
+			contracts[platform] = address
+		}
+	}
+
+	// Get Total Supply if coin has it
+	totalSupply := 0.0
+	if coinData.MarketData.TotalSupply != nil {
+		totalSupply = *coinData.MarketData.TotalSupply
+	}
+
+	// Get Change Percents
+	changePercents := [][]string{
+		{"24H", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage24h)},
+		{"7D", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage7d)},
+		{"14D", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage14d)},
+		{"30D", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage30d)},
+		{"60D", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage60d)},
+		{"200D", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage200d)},
+		{"1Y", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage1y)},
+	}
+
+	for i, row := range changePercents {
+		change := row[1]
+		if string(change[0]) == "-" {
+			change = utils.DownArrow + " " + change[1:]
+		} else {
+			change = utils.UpArrow + " " + change
+		}
+		changePercents[i][1] = change
+	}
+
+	// Get community and developer activity
+	activity := Activity{-1, -1, -1, -1, -1}
+	if dev := coinData.DeveloperData; dev != nil {
+		activity.GitHubStars = count(dev.Stars)
+		activity.Commits4Weeks = count(dev.CommitsCount4Weeks)
+		if dev.TotalIssues != nil && dev.ClosedIssues != nil {
+			activity.OpenIssues = int(*dev.TotalIssues) - int(*dev.ClosedIssues)
+		}
+	}
+	if community := coinData.CommunityData; community != nil {
+		activity.RedditSubscribers = count(community.RedditSubscribers)
+		activity.TwitterFollowers = count(community.TwitterFollowers)
+	}
+
+	// Get ATH, ATL and Last update times, which are not served for all
+	// coins and currencies
+	athDate, err := formatTime(coinData.MarketData.ATHDate[vsCurrency])
+	if err != nil {
+		return CoinDetails{}, err
+	}
+
+	atlDate, err := formatTime(coinData.MarketData.ATLDate[vsCurrency])
+	if err != nil {
+		return CoinDetails{}, err
+	}
+
+	lastUpdate, err := formatTime(coinData.LastUpdated)
+	if err != nil {
+		return CoinDetails{}, err
+	}
+
+	return CoinDetails{
+		Name:           coinData.Name,
+		Symbol:         strings.ToUpper(coinData.Symbol),
+		Rank:           fmt.Sprintf("%d", coinData.MarketCapRank),
+		BlockTime:      fmt.Sprintf("%d", coinData.BlockTimeInMin),
+		MarketCap:      coinData.MarketData.MarketCap[vsCurrency],
+		Description:    stripHTML(coinData.Description["en"]),
+		Website:        website,
+		Whitepaper:     coinData.Links.Whitepaper,
+		Repos:          repos,
+		Subreddit:      coinData.Links.SubredditURL,
+		Contracts:      contracts,
+		Explorers:      explorerLinks,
+		ATH:            coinData.MarketData.ATH[vsCurrency],
+		ATHDate:        athDate,
+		ATL:            coinData.MarketData.ATL[vsCurrency],
+		ATLDate:        atlDate,
+		High24:         coinData.MarketData.High24[vsCurrency],
+		Low24:          coinData.MarketData.Low24[vsCurrency],
+		TotalVolume:    coinData.MarketData.TotalVolume[vsCurrency],
+		ChangePercents: changePercents,
+		TotalSupply:    totalSupply,
+		CurrentSupply:  coinData.MarketData.CirculatingSupply,
+		LastUpdate:     lastUpdate,
+		Activity:       activity,
+	}, nil
+}
+
+// formatTime formats a time served by CoinGecko to be shown, a missing time
+// is left empty
+func formatTime(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	t, err := time.Parse("2006-01-02T15:04:05.000Z", value)
+	if err != nil {
+		return "", err
+	}
+
+	return t.Format(time.RFC822), nil
+}
+
+// htmlTag matches HTML tags in descriptions of coins
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// stripHTML returns text with HTML tags removed and entities unescaped
+func stripHTML(text string) string {
+	text = htmlTag.ReplaceAllString(text, "")
+	return strings.TrimSpace(html.UnescapeString(text))
+}
+
+// count returns the value of a statistic, or -1 if it is unknown
+func count(n *uint) int {
+	if n == nil {
+		return -1
+	}
+	return int(*n)
+}
+
+// geckoTicker is used to unmarshal tickers from the CoinGecko API
+type geckoTicker struct {
+	Base   string `json:"base"`
+	Target string `json:"target"`
+	Market struct {
+		Name string `json:"name"`
+	} `json:"market"`
+	Last            float64            `json:"last"`
+	ConvertedLast   map[string]float64 `json:"converted_last"`
+	ConvertedVolume map[string]float64 `json:"converted_volume"`
+	TrustScore      *string            `json:"trust_score"`
+	Spread          float64            `json:"bid_ask_spread_percentage"`
+	IsAnomaly       bool               `json:"is_anomaly"`
+	IsStale         bool               `json:"is_stale"`
+}
+
+// Tickers returns the pairs the coin specified by id trades in, ordered by
+// volume
+func (p *CoinGeckoProvider) Tickers(ctx context.Context, id string) ([]Ticker, error) {
+	params := url.Values{}
+	params.Set("order", "volume_desc")
+	reqURL := fmt.Sprintf("%s/coins/%s/tickers?%s", Config().CoinGeckoURL, url.PathEscape(id), params.Encode())
+
+	data := struct {
+		Tickers []geckoTicker `json:"tickers"`
+	}{}
+	if err := p.getJSON(ctx, reqURL, &data); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]Ticker, len(data.Tickers))
+	for i, val := range data.Tickers {
+		trustScore := ""
+		if val.TrustScore != nil {
+			trustScore = *val.TrustScore
+		}
+
+		tickers[i] = Ticker{
+			Exchange:   val.Market.Name,
+			Base:       val.Base,
+			Target:     val.Target,
+			Last:       val.Last,
+			LastUSD:    val.ConvertedLast["usd"],
+			VolumeUSD:  val.ConvertedVolume["usd"],
+			Spread:     val.Spread,
+			TrustScore: trustScore,
+			IsAnomaly:  val.IsAnomaly,
+			IsStale:    val.IsStale,
+		}
+	}
+
+	return tickers, nil
+}
+
+// Search returns coins whose name or symbol match query, best matches first
+func (p *CoinGeckoProvider) Search(ctx context.Context, query string) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	reqURL := fmt.Sprintf("%s/search?%s", Config().CoinGeckoURL, params.Encode())
+
+	data := struct {
+		Coins []struct {
+			ID            string `json:"id"`
+			Symbol        string `json:"symbol"`
+			Name          string `json:"name"`
+			MarketCapRank *int   `json:"market_cap_rank"`
+		} `json:"coins"`
+	}{}
+	if err := p.getJSON(ctx, reqURL, &data); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, len(data.Coins))
+	for i, val := range data.Coins {
+		rank := 0
+		if val.MarketCapRank != nil {
+			rank = *val.MarketCapRank
+		}
+
+		results[i] = SearchResult{
+			ID:            val.ID,
+			Symbol:        val.Symbol,
+			Name:          val.Name,
+			MarketCapRank: rank,
+		}
+	}
+
+	return results, nil
+}
+
+// Trending returns coins trending in searches on CoinGecko, most trending
+// first
+func (p *CoinGeckoProvider) Trending(ctx context.Context) ([]TrendingCoin, error) {
+	reqURL := fmt.Sprintf("%s/search/trending", Config().CoinGeckoURL)
+
+	data := struct {
+		Coins []struct {
+			Item struct {
+				ID     string `json:"id"`
+				Symbol string `json:"symbol"`
+				Name   string `json:"name"`
+				Score  int    `json:"score"`
+			} `json:"item"`
+		} `json:"coins"`
+	}{}
+	if err := p.getJSON(ctx, reqURL, &data); err != nil {
+		return nil, err
+	}
+
+	coins := make([]TrendingCoin, len(data.Coins))
+	for i, val := range data.Coins {
+		coins[i] = TrendingCoin{
+			ID:     val.Item.ID,
+			Symbol: val.Item.Symbol,
+			Name:   val.Item.Name,
+			Score:  val.Item.Score,
+		}
+	}
+	sort.SliceStable(coins, func(i, j int) bool {
+		return coins[i].Score < coins[j].Score
+	})
+
+	return coins, nil
+}
+
+// Global returns market wide data such as the total market cap
+func (p *CoinGeckoProvider) Global(ctx context.Context) (GlobalMarket, error) {
+	reqURL := fmt.Sprintf("%s/global", Config().CoinGeckoURL)
+
+	data := struct {
+		Data struct {
+			ActiveCoins        int                `json:"active_cryptocurrencies"`
+			Markets            int                `json:"markets"`
+			TotalMarketCap     map[string]float64 `json:"total_market_cap"`
+			TotalVolume        map[string]float64 `json:"total_volume"`
+			Dominance          map[string]float64 `json:"market_cap_percentage"`
+			MarketCapChange24h float64            `json:"market_cap_change_percentage_24h_usd"`
+		} `json:"data"`
+	}{}
+	if err := p.getJSON(ctx, reqURL, &data); err != nil {
+		return GlobalMarket{}, err
+	}
+
+	return GlobalMarket{
+		TotalMarketCap:     data.Data.TotalMarketCap,
+		TotalVolume:        data.Data.TotalVolume,
+		Dominance:          data.Data.Dominance,
+		MarketCapChange24h: data.Data.MarketCapChange24h,
+		ActiveCoins:        data.Data.ActiveCoins,
+		Markets:            data.Data.Markets,
+	}, nil
+}
+
+// coinIDsRetryDelay is how often CoinCap IDs of coins are looked up again
+// while none are known, such as while they are first fetched
+const coinIDsRetryDelay = 5 * time.Second
+
+// coinCapIDs returns the CoinCap IDs of the coins specified by ids, and the
+// coin IDs they map back to. Coins without a CoinCap ID are left out.
+func (p *CoinGeckoProvider) coinCapIDs(ids []string) ([]string, map[string]string) {
+	p.coinIDsOnce.Do(func() {
+		p.coinIDs = NewCoinIDMap()
+		p.coinIDs.Load(context.Background())
+	})
+
+	coinCapIDs := []string{}
+	coinGeckoIDs := make(map[string]string, len(ids))
+	for _, id := range ids {
+		coinCapID := p.coinIDs.Get(id).CoinCapID
+		if coinCapID == "" {
+			continue
+		}
+
+		coinCapIDs = append(coinCapIDs, coinCapID)
+		coinGeckoIDs[coinCapID] = id
+	}
+
+	return coinCapIDs, coinGeckoIDs
+}
+
+// LivePrices uses a CoinCap websocket to stream realtime prices of coins
+// specified by ids, which are mapped to their CoinCap IDs. The prices are
+// sent on the dataChannel. It waits while none of the coins have a known
+// CoinCap ID.
+func (p *CoinGeckoProvider) LivePrices(ctx context.Context, ids []string, dataChannel chan map[string]float64) error {
+	coinCapIDs, coinGeckoIDs := p.coinCapIDs(ids)
+	for len(coinCapIDs) == 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(coinIDsRetryDelay):
+		}
+		coinCapIDs, coinGeckoIDs = p.coinCapIDs(ids)
+	}
+
+	url := fmt.Sprintf("%s/prices?assets=%s", Config().CoinCapWSURL, strings.Join(coinCapIDs, ","))
+	c, err := dial(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	// Close connection once the context is cancelled to stop reads
+	go func() {
+		<-ctx.Done()
+		c.Close()
+	}()
+
+	for {
+		_, message, err := c.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		msg := make(map[string]string)
+		err = json.Unmarshal(message, &msg)
+		if err != nil {
+			return err
+		}
+
+		prices := make(map[string]float64, len(msg))
+		for coinCapID, price := range msg {
+			id, ok := coinGeckoIDs[coinCapID]
+			if !ok {
+				continue
+			}
+			if val, err := strconv.ParseFloat(price, 64); err == nil {
+				prices[id] = val
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case dataChannel <- prices:
+		}
+	}
+}
+
+// coinListFile caches the list of all CoinGecko coins, which changes slowly
+// and is large enough to be worth keeping across runs
+const coinListFile = "coingecko-coins.json"
+
+// coinListMaxAge is how long the cached coin list is used before refetching
+const coinListMaxAge = 24 * time.Hour
+
+// rankedCoins is the number of top CoinGecko coins whose rank and market cap
+// are fetched to tell apart coins sharing a symbol
+const rankedCoins = 1000
+
+// CoinIDs returns IDs of all CoinGecko coins, with the ranks and market caps
+// of the top coins. CoinCap IDs of the top 2000 CoinCap coins are matched to
+// them by symbol, name and rank. If only some of the data can be fetched,
+// the IDs which can be made out are returned along with the error met.
+func (p *CoinGeckoProvider) CoinIDs(ctx context.Context) ([]CoinID, error) {
+	var coinList []CoinListItem
+	var listErr, coinCapErr error
+	topCoins := make([][]CoinMarket, rankedCoins/maxPerPage)
+	topErrs := make([]error, len(topCoins))
+	coinCapData := CoinCapData{}
+
+	var wg sync.WaitGroup
+	wg.Add(2 + len(topCoins))
+
+	// Get all CoinGecko IDs
+	go func() {
+		defer wg.Done()
+		coinList, listErr = p.coinList(ctx)
+	}()
+
+	// Get ranks of top CoinGecko coins
+	for i := range topCoins {
+		go func(i int) {
+			defer wg.Done()
+			topCoins[i], topErrs[i] = p.Markets(ctx, "usd", []string{}, maxPerPage, i+1)
+		}(i)
+	}
+
+	// Get CoinCapIDs
+	go func() {
+		defer wg.Done()
+		coinCapErr = p.getJSON(ctx, Config().CoinCapURL+"/assets?limit=2000", &coinCapData)
+	}()
+
+	wg.Wait()
+
+	// Without the full list, coins can still be looked up among top coins
+	if listErr != nil && len(topCoins[0]) == 0 {
+		return nil, listErr
+	}
+
+	coins := make(map[string]*CoinID, len(coinList))
+	for _, val := range coinList {
+		coins[val.ID] = &CoinID{
+			CoinGeckoID: val.ID,
+			Symbol:      strings.ToUpper(val.Symbol),
+			Name:        val.Name,
+		}
+	}
+
+	for _, page := range topCoins {
+		for _, val := range page {
+			coins[val.ID] = &CoinID{
+				CoinGeckoID: val.ID,
+				Symbol:      strings.ToUpper(val.Symbol),
+				Name:        val.Name,
+				Rank:        val.MarketCapRank,
+				MarketCap:   val.MarketCap,
+			}
+		}
+	}
+
+	matchCoinCapIDs(coins, coinCapData.Data)
+
+	coinIDs := make([]CoinID, 0, len(coins))
+	for _, coinID := range coins {
+		coinIDs = append(coinIDs, *coinID)
+	}
+
+	// Report what is missing
+	switch {
+	case listErr != nil:
+		return coinIDs, fmt.Errorf("failed to fetch list of coins: %w", listErr)
+	case coinCapErr != nil:
+		return coinIDs, fmt.Errorf("failed to fetch CoinCap IDs: %w", coinCapErr)
+	}
+	for _, err := range topErrs {
+		if err != nil {
+			return coinIDs, fmt.Errorf("failed to fetch ranks of top coins: %w", err)
+		}
+	}
+
+	return coinIDs, nil
+}
+
+// matchCoinCapIDs sets CoinCap IDs of coins by matching CoinCap assets to
+// CoinGecko coins with the same symbol. Symbols are shared by many coins, so
+// candidates are scored on their IDs, names and ranks. Assets are matched in
+// order of rank and each coin is matched at most once.
+func matchCoinCapIDs(coins map[string]*CoinID, assets []CoinCapAsset) {
+	bySymbol := make(map[string][]*CoinID)
+	for _, coin := range coins {
+		bySymbol[coin.Symbol] = append(bySymbol[coin.Symbol], coin)
+	}
+
+	for _, asset := range assets {
+		candidates := bySymbol[strings.ToUpper(asset.Symbol)]
+		rank, _ := strconv.Atoi(asset.Rank)
+
+		var best *CoinID
+		bestScore := 0
+		for _, coin := range candidates {
+			if coin.CoinCapID != "" {
+				continue
+			}
+
+			score := matchScore(asset, rank, *coin)
+			if best == nil || score > bestScore {
+				best = coin
+				bestScore = score
+			}
+		}
+
+		// Accept a likely match, or the only coin with the symbol unless it
+		// is clearly a different coin
+		if best != nil && (bestScore >= 2 || (len(candidates) == 1 && bestScore >= 0)) {
+			best.CoinCapID = asset.ID
+		}
+	}
+}
+
+// matchScore scores how likely a CoinCap asset of the given rank is the
+// same coin as a CoinGecko coin
+func matchScore(asset CoinCapAsset, rank int, coin CoinID) int {
+	score := 0
+
+	// Both APIs often use the same slug as ID
+	if asset.ID == coin.CoinGeckoID {
+		score += 4
+	}
+
+	assetName := normaliseName(asset.Name)
+	coinName := normaliseName(coin.Name)
+	switch {
+	case assetName == "" || coinName == "":
+	case assetName == coinName:
+		score += 3
+	case strings.Contains(assetName, coinName) || strings.Contains(coinName, assetName):
+		score++
+	}
+
+	// Ranks differ between APIs but should be close for the same coin
+	if rank > 0 && coin.Rank > 0 {
+		diff := rank - coin.Rank
+		if diff < 0 {
+			diff = -diff
+		}
+
+		tolerance := rank / 5
+		if tolerance < 10 {
+			tolerance = 10
+		}
+
+		switch {
+		case diff <= 5:
+			score += 2
+		case diff <= tolerance:
+			score++
+		default:
+			score -= 2
+		}
+	}
+
+	return score
+}
+
+// normaliseName lower cases a coin name and strips all but letters and
+// digits, so names differing only in spacing or punctuation compare equal
+func normaliseName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// coinList returns all coins listed by CoinGecko, using the local cache
+// while it is fresh, or when the list cannot be fetched
+func (p *CoinGeckoProvider) coinList(ctx context.Context) ([]CoinListItem, error) {
+	cached := []CoinListItem{}
+	written, ok := readCache(coinListFile, &cached)
+	if ok && time.Since(written) < coinListMaxAge {
+		return cached, nil
+	}
+
+	coins := []CoinListItem{}
+	if err := p.getJSON(ctx, Config().CoinGeckoURL+"/coins/list", &coins); err != nil {
+		if ok {
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	writeCache(coinListFile, coins)
+
+	return coins, nil
+}
+
+// VsCurrencies returns codes of currencies CoinGecko can price coins in
+func (p *CoinGeckoProvider) VsCurrencies(ctx context.Context) ([]string, error) {
+	currencies := []string{}
+	if err := p.getJSON(ctx, Config().CoinGeckoURL+"/simple/supported_vs_currencies", &currencies); err != nil {
+		return nil, err
+	}
+
+	return currencies, nil
+}
+
+// CurrencyRates returns USD rates of currencies from CoinCap
+func (p *CoinGeckoProvider) CurrencyRates(ctx context.Context) ([]CurrencyRate, error) {
+	data := utils.AllCurrencyData{}
+	if err := p.getJSON(ctx, Config().CoinCapURL+"/rates", &data); err != nil {
+		return nil, err
+	}
+
+	// Iterate over currencies
+	rates := []CurrencyRate{}
+	for _, curr := range data.Data {
+		rate, err := strconv.ParseFloat(curr.RateUSD, 64)
+		if err == nil {
+			rates = append(rates, CurrencyRate{
+				ID:             curr.ID,
+				Symbol:         curr.Symbol,
+				CurrencySymbol: curr.CurrencySymbol,
+				Type:           curr.Type,
+				RateUSD:        rate,
+			})
+		}
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,243 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMatchScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		asset CoinCapAsset
+		rank  int
+		coin  CoinID
+		want  int
+	}{
+		{
+			name:  "same ID, name and rank",
+			asset: CoinCapAsset{ID: "bitcoin", Name: "Bitcoin"},
+			rank:  1,
+			coin:  CoinID{CoinGeckoID: "bitcoin", Name: "Bitcoin", Rank: 1},
+			want:  4 + 3 + 2,
+		},
+		{
+			name:  "same name without ranks",
+			asset: CoinCapAsset{ID: "binance-coin", Name: "BNB"},
+			coin:  CoinID{CoinGeckoID: "binancecoin", Name: "BNB"},
+			want:  3,
+		},
+		{
+			name:  "names differing in punctuation",
+			asset: CoinCapAsset{ID: "shiba-inu", Name: "SHIBA-INU"},
+			coin:  CoinID{CoinGeckoID: "shiba", Name: "Shiba Inu"},
+			want:  3,
+		},
+		{
+			name:  "name contained in the other",
+			asset: CoinCapAsset{ID: "polygon", Name: "Polygon"},
+			coin:  CoinID{CoinGeckoID: "matic-network", Name: "Polygon Ecosystem"},
+			want:  1,
+		},
+		{
+			name:  "rank within tolerance",
+			asset: CoinCapAsset{ID: "a", Name: "A"},
+			rank:  100,
+			coin:  CoinID{CoinGeckoID: "b", Name: "B", Rank: 115},
+			want:  1,
+		},
+		{
+			name:  "rank far off",
+			asset: CoinCapAsset{ID: "a", Name: "A"},
+			rank:  10,
+			coin:  CoinID{CoinGeckoID: "b", Name: "B", Rank: 500},
+			want:  -2,
+		},
+		{
+			name:  "unknown name",
+			asset: CoinCapAsset{ID: "a"},
+			coin:  CoinID{CoinGeckoID: "b", Name: "B"},
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchScore(tt.asset, tt.rank, tt.coin); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchCoinCapIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		coins  []CoinID
+		assets []CoinCapAsset
+		want   map[string]string // CoinCap IDs by CoinGecko ID
+	}{
+		{
+			name: "best of coins sharing a symbol",
+			coins: []CoinID{
+				{CoinGeckoID: "uniswap", Symbol: "UNI", Name: "Uniswap", Rank: 20},
+				{CoinGeckoID: "unicorn-token", Symbol: "UNI", Name: "Unicorn Token"},
+			},
+			assets: []CoinCapAsset{{ID: "uniswap", Symbol: "uni", Name: "Uniswap", Rank: "18"}},
+			want:   map[string]string{"uniswap": "uniswap", "unicorn-token": ""},
+		},
+		{
+			name: "weak match among coins sharing a symbol",
+			coins: []CoinID{
+				{CoinGeckoID: "first", Symbol: "ABC", Name: "First"},
+				{CoinGeckoID: "second", Symbol: "ABC", Name: "Second"},
+			},
+			assets: []CoinCapAsset{{ID: "third", Symbol: "ABC", Name: "Third"}},
+			want:   map[string]string{"first": "", "second": ""},
+		},
+		{
+			name:   "only coin with the symbol",
+			coins:  []CoinID{{CoinGeckoID: "foo", Symbol: "FOO", Name: "Foo"}},
+			assets: []CoinCapAsset{{ID: "foo-token", Symbol: "FOO", Name: "Bar"}},
+			want:   map[string]string{"foo": "foo-token"},
+		},
+		{
+			name:   "only coin with the symbol, clearly different",
+			coins:  []CoinID{{CoinGeckoID: "foo", Symbol: "FOO", Name: "Foo", Rank: 900}},
+			assets: []CoinCapAsset{{ID: "bar", Symbol: "FOO", Name: "Bar", Rank: "3"}},
+			want:   map[string]string{"foo": ""},
+		},
+		{
+			name: "coins matched at most once",
+			coins: []CoinID{
+				{CoinGeckoID: "tether", Symbol: "USDT", Name: "Tether", Rank: 3},
+			},
+			assets: []CoinCapAsset{
+				{ID: "tether", Symbol: "USDT", Name: "Tether", Rank: "3"},
+				{ID: "tether-bridged", Symbol: "USDT", Name: "Tether", Rank: "200"},
+			},
+			want: map[string]string{"tether": "tether"},
+		},
+		{
+			name:   "asset without coins",
+			coins:  []CoinID{{CoinGeckoID: "bitcoin", Symbol: "BTC", Name: "Bitcoin"}},
+			assets: []CoinCapAsset{{ID: "ethereum", Symbol: "ETH", Name: "Ethereum"}},
+			want:   map[string]string{"bitcoin": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coins := make(map[string]*CoinID)
+			for i := range tt.coins {
+				coins[tt.coins[i].CoinGeckoID] = &tt.coins[i]
+			}
+
+			matchCoinCapIDs(coins, tt.assets)
+
+			for id, want := range tt.want {
+				if got := coins[id].CoinCapID; got != want {
+					t.Errorf("CoinCap ID of %s is %q, want %q", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+// fakeConn serves messages as websocket frames, then fails reads
+type fakeConn struct {
+	messages []string
+}
+
+func (c *fakeConn) ReadMessage() (int, []byte, error) {
+	if len(c.messages) == 0 {
+		return 0, nil, fmt.Errorf("websocket closed")
+	}
+
+	message := c.messages[0]
+	c.messages = c.messages[1:]
+	return 1, []byte(message), nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func TestLivePrices(t *testing.T) {
+	restoreTraffic(t)
+
+	dialed := ""
+	trafficMutex.Lock()
+	dialWebsocket = func(ctx context.Context, url string) (wsConn, error) {
+		dialed = url
+		return &fakeConn{messages: []string{`{"binance-coin": "300.5", "unknown": "1", "bitcoin": "NaN?"}`}}, nil
+	}
+	trafficMutex.Unlock()
+
+	p := NewCoinGeckoProvider()
+	p.coinIDsOnce.Do(func() {
+		p.coinIDs = NewCoinIDMap()
+		p.coinIDs.Set([]CoinID{
+			{CoinGeckoID: "binancecoin", CoinCapID: "binance-coin"},
+			{CoinGeckoID: "bitcoin", CoinCapID: "bitcoin"},
+			{CoinGeckoID: "no-coincap-id"},
+		})
+	})
+
+	dataChannel := make(chan map[string]float64, 1)
+	err := p.LivePrices(context.Background(), []string{"binancecoin", "bitcoin", "no-coincap-id"}, dataChannel)
+	if err == nil {
+		t.Error("expected error once the stream breaks")
+	}
+
+	// Coins are streamed by CoinCap ID and served by coin ID
+	if want := Config().CoinCapWSURL + "/prices?assets=binance-coin,bitcoin"; dialed != want {
+		t.Errorf("dialed %s, want %s", dialed, want)
+	}
+	if got, want := <-dataChannel, map[string]float64{"binancecoin": 300.5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got prices %v, want %v", got, want)
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		err   bool
+	}{
+		{value: "2021-11-10T14:24:11.849Z", want: "10 Nov 21 14:24 UTC"},
+		{value: "", want: ""},
+		{value: "10 Nov 2021", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := formatTime(tt.value)
+		if tt.err {
+			if err == nil {
+				t.Errorf("formatTime(%q) = %q, want error", tt.value, got)
+			}
+			continue
+		}
+
+		if err != nil || got != tt.want {
+			t.Errorf("formatTime(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
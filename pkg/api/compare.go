@@ -0,0 +1,126 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// compareMaxAge is how long histories of compared coins are served before
+// they are fetched again
+const compareMaxAge = time.Duration(60) * time.Second
+
+// GetCompareHistory serves the price history of the coins specified by ids
+// on dataChannel, as percent change from the start of the selected interval.
+// Prices are in USD, as changes barely depend on the currency. The key of
+// the interval, such as "90d", is received through intervalChannel, "90d" is
+// served until then.
+func GetCompareHistory(ctx context.Context, ids []string, intervalChannel chan string, dataChannel chan CompareData) error {
+	interval := "90d"
+	var m sync.Mutex
+
+	// Update interval when a new one is received
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case newInterval := <-intervalChannel:
+				m.Lock()
+				interval = newInterval
+				m.Unlock()
+			}
+		}
+	}()
+
+	// Interval last served and when, histories are fetched again only once
+	// it changes or they are old
+	served := ""
+	servedAt := time.Time{}
+
+	return poll(ctx, time.Duration(3)*time.Second, func() error {
+		m.Lock()
+		current := interval
+		m.Unlock()
+
+		if current == served && time.Since(servedAt) < compareMaxAge {
+			return nil
+		}
+
+		// Fetch histories concurrently
+		changes := make([][]float64, len(ids))
+		var eg errgroup.Group
+		for i, id := range ids {
+			id := id
+			change := &changes[i]
+			eg.Go(func() error {
+				data, err := CurrentProvider().MarketChart(ctx, id, "usd", intervalDays[current])
+				if err != nil {
+					return err
+				}
+
+				*change = percentChange(data.Prices)
+				if *change == nil {
+					return fmt.Errorf("no price history for %s", id)
+				}
+				return nil
+			})
+		}
+
+		if err := eg.Wait(); err != nil {
+			return err
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case dataChannel <- CompareData{Interval: current, Changes: changes}:
+		}
+
+		served, servedAt = current, time.Now()
+		return nil
+	}, func(err error) {
+		select {
+		case <-ctx.Done():
+		case dataChannel <- CompareData{Err: err}:
+		}
+	})
+}
+
+// percentChange returns prices as percent change from the first non zero
+// price, prices before it are left out. It returns nil if there is none.
+func percentChange(prices []float64) []float64 {
+	for start, first := range prices {
+		if first == 0 {
+			continue
+		}
+
+		changes := make([]float64, len(prices)-start)
+		for i, price := range prices[start:] {
+			changes[i] = (price/first - 1) * 100
+		}
+		return changes
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// currencyRatesFile is the cache file of currency rates
+const currencyRatesFile = "currency-rates.json"
+
+// currencyRatesMaxAge is how long cached currency rates are used before
+// refreshing them
+const currencyRatesMaxAge = time.Hour
+
+// CachedCurrencyRates returns the currency rates last cached, however old,
+// and whether they have expired. Rates of subunits such as satoshis are
+// included. ok is false if no rates are cached.
+func CachedCurrencyRates() (rates []CurrencyRate, expired, ok bool) {
+	written, ok := readCache(currencyRatesFile, &rates)
+	if !ok {
+		return nil, false, false
+	}
+
+	return withSubunits(rates), time.Since(written) > currencyRatesMaxAge, true
+}
+
+// FetchCurrencyRates fetches currency rates from the current provider and
+// caches them. Rates of subunits such as satoshis are included.
+func FetchCurrencyRates(ctx context.Context) ([]CurrencyRate, error) {
+	provider, ok := CurrentProvider().(CurrencyRatesProvider)
+	if !ok {
+		return nil, unsupported("currency rates")
+	}
+
+	rates, err := provider.CurrencyRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	writeCache(currencyRatesFile, rates)
+
+	return withSubunits(rates), nil
+}
+
+// withSubunits adds rates of subunits, such as satoshis, of currencies in
+// rates
+func withSubunits(rates []CurrencyRate) []CurrencyRate {
+	byID := make(map[string]CurrencyRate, len(rates))
+	for _, rate := range rates {
+		byID[rate.ID] = rate
+	}
+
+	for _, unit := range subunits {
+		parent, ok := byID[unit.ParentID]
+		if _, exists := byID[unit.ID]; exists || !ok {
+			continue
+		}
+
+		rates = append(rates, CurrencyRate{
+			ID:      unit.ID,
+			Symbol:  unit.Symbol,
+			Type:    parent.Type,
+			RateUSD: parent.RateUSD * unit.Size,
+		})
+	}
+
+	return rates
+}
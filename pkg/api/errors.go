@@ -0,0 +1,119 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported is wrapped by errors met when the current provider does not
+// serve what was asked for. Nothing is served, rather than anything failing.
+var ErrUnsupported = errors.New("not served by the current market data provider")
+
+// APIError is returned when an API responds with an error status.
+// RetryAfter is set if the API asked for requests to be paused.
+type APIError struct {
+	API        string
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error (%d %s): %s", e.API, e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// errorMessage extracts the error message from an error response body.
+// CoinCap and CoinGecko respond with either {"error": "..."} or
+// {"status": {"error_code": ..., "error_message": "..."}}
+func errorMessage(body []byte) string {
+	data := struct {
+		Error  string `json:"error"`
+		Status struct {
+			ErrorCode    int    `json:"error_code"`
+			ErrorMessage string `json:"error_message"`
+		} `json:"status"`
+	}{}
+
+	if err := json.Unmarshal(body, &data); err == nil {
+		if data.Status.ErrorMessage != "" {
+			return data.Status.ErrorMessage
+		}
+		if data.Error != "" {
+			return data.Error
+		}
+	}
+
+	return strings.TrimSpace(string(body))
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or a HTTP date
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// IsRetryable reports whether an operation that failed with err may succeed
+// if tried again. Client errors, such as a rejected API key or an unknown
+// coin, or data not served by the current provider, are not retryable. Rate
+// limits, server errors and network errors are.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrUnsupported) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests,
+			apiErr.StatusCode == http.StatusRequestTimeout,
+			apiErr.StatusCode >= 500:
+			return true
+		default:
+			return false
+		}
+	}
+
+	return true
+}
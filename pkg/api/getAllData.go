@@ -18,128 +18,258 @@ package api
 
 import (
 	"context"
-	"fmt"
+	"sort"
 	"time"
 
 	"github.com/Gituser143/cryptgo/pkg/utils"
-	gecko "github.com/superoo7/go-gecko/v3"
-	geckoTypes "github.com/superoo7/go-gecko/v3/types"
+	"golang.org/x/sync/errgroup"
 )
 
-func getTopNCoins(n int) (geckoTypes.CoinsMarket, error) {
-	geckoClient := gecko.NewClient(nil)
+// maxPerPage is the largest page of coins served by a Provider
+const maxPerPage = 250
 
-	vsCurrency := "usd"
+// getTopNCoins fetches the top n coins by market cap, priced in vsCurrency,
+// among coins in the category specified by its ID, or all coins if it is
+// empty. Pages of coins are fetched concurrently and merged in order.
+func getTopNCoins(ctx context.Context, n int, vsCurrency, category string) ([]CoinMarket, error) {
 	ids := []string{}
 
-	if n > 1000 {
-		return nil, fmt.Errorf("page size limit is 1000")
+	pages := (n + maxPerPage - 1) / maxPerPage
+	perPage := maxPerPage
+	if pages == 1 {
+		perPage = n
 	}
 
-	perPage := n
-	page := 1
+	results := make([][]CoinMarket, pages)
+	var eg errgroup.Group
+	for i := range results {
+		page := i + 1
+		result := &results[i]
+		eg.Go(func() error {
+			var coins []CoinMarket
+			var err error
+			if category != "" {
+				provider, ok := CurrentProvider().(CategoriesProvider)
+				if !ok {
+					return unsupported("categories")
+				}
+				coins, err = provider.CategoryMarkets(ctx, category, vsCurrency, perPage, page)
+			} else {
+				coins, err = CurrentProvider().Markets(ctx, vsCurrency, ids, perPage, page)
+			}
+			*result = coins
+			return err
+		})
+	}
 
-	sparkline := true
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
 
-	pcp := geckoTypes.PriceChangePercentageObject
-	priceChangePercentage := []string{pcp.PCP1h, pcp.PCP24h, pcp.PCP7d, pcp.PCP14d, pcp.PCP30d, pcp.PCP200d, pcp.PCP1y}
+	return mergeCoins(n, results), nil
+}
 
-	order := geckoTypes.OrderTypeObject.MarketCapDesc
-	coinDataPointer, err := geckoClient.CoinsMarket(vsCurrency, ids, order, perPage, page, sparkline, priceChangePercentage)
+// getCoins fetches market data of the coins specified by ids, priced in
+// vsCurrency, in pages fetched concurrently
+func getCoins(ctx context.Context, ids []string, vsCurrency string) ([]CoinMarket, error) {
+	results := make([][]CoinMarket, (len(ids)+maxPerPage-1)/maxPerPage)
+	var eg errgroup.Group
+	for i := range results {
+		end := (i + 1) * maxPerPage
+		if end > len(ids) {
+			end = len(ids)
+		}
+		pageIDs := ids[i*maxPerPage : end]
+		result := &results[i]
+		eg.Go(func() error {
+			coins, err := CurrentProvider().Markets(ctx, vsCurrency, pageIDs, len(pageIDs), 1)
+			*result = coins
+			return err
+		})
+	}
 
-	if err != nil {
+	if err := eg.Wait(); err != nil {
 		return nil, err
 	}
 
-	coinData := *coinDataPointer
+	return mergeCoins(len(ids), results), nil
+}
+
+// GetHoldings serves market data of the coins held, specified by ids, on
+// dataChannel, once. Prices are in the currency currently set in
+// vsCurrency.
+func GetHoldings(ctx context.Context, ids []string, vsCurrency *Currency, dataChannel chan CoinData) error {
+	// Sort IDs so requests are the same every run
+	ids = append([]string{}, ids...)
+	sort.Strings(ids)
+
+	code := vsCurrency.Code()
+	data := CoinData{Type: "PORTFOLIO", VsCurrency: code}
+	coins, err := getCoins(ctx, ids, code)
+	if err != nil {
+		data = CoinData{Type: "PORTFOLIO", Err: err}
+	}
+	data.Holdings = coins
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case dataChannel <- data:
+	}
 
-	return coinData, nil
+	return nil
 }
 
-// GetPercentageChangeForDuration returns price change percentage given a
-// CoinsMarketItem and a duration, If the specified duration does not exist, 24
-// Hour change percent is returned
-func GetPercentageChangeForDuration(coinData geckoTypes.CoinsMarketItem, duration string) float64 {
-
-	m := map[string]*float64{
-		"1h":   coinData.PriceChangePercentage1hInCurrency,
-		"24h":  coinData.PriceChangePercentage24hInCurrency,
-		"7d":   coinData.PriceChangePercentage7dInCurrency,
-		"14d":  coinData.PriceChangePercentage14dInCurrency,
-		"30d":  coinData.PriceChangePercentage30dInCurrency,
-		"200d": coinData.PriceChangePercentage200dInCurrency,
-		"1y":   coinData.PriceChangePercentage1yInCurrency,
+// mergeCoins joins pages of coins, dropping coins seen on earlier pages as
+// ranks can shift between requests, and returns at most n coins
+func mergeCoins(n int, pages [][]CoinMarket) []CoinMarket {
+	coins := make([]CoinMarket, 0, n)
+	seen := make(map[string]bool)
+
+	for _, page := range pages {
+		for _, coin := range page {
+			if len(coins) == n {
+				return coins
+			}
+			if !seen[coin.ID] {
+				seen[coin.ID] = true
+				coins = append(coins, coin)
+			}
+		}
 	}
 
-	if percentageDuration, isPresent := m[duration]; isPresent && percentageDuration != nil {
-		return *percentageDuration
+	return coins
+}
+
+// GetPercentageChangeForDuration returns price change percentage given a
+// CoinMarket and a duration, If the specified duration does not exist, 24
+// Hour change percent is returned
+func GetPercentageChangeForDuration(coinData CoinMarket, duration string) float64 {
+	if percentageDuration, isPresent := coinData.PriceChangePercentage[duration]; isPresent {
+		return percentageDuration
 	}
-	return coinData.PriceChangePercentage24h
+	return coinData.PriceChangePercentage["24h"]
 }
 
-// GetAssets serves data about top 100 coins for the main page
-func GetAssets(ctx context.Context, dataChannel chan AssetData, sendData *bool) error {
+// GetAssets serves data about the top n coins for the main page, among
+// coins in the category currently set in category. Favourites and coins held
+// which are not among them are served too, so they can be shown whatever
+// their rank. Prices are fetched in the currency currently set in
+// vsCurrency.
+func GetAssets(ctx context.Context, n int, vsCurrency *Currency, category *Category, dataChannel chan AssetData, sendData *bool) error {
 
-	return utils.LoopTick(ctx, time.Duration(10)*time.Second, func(errChan chan error) {
-		var finalErr error
-		var data AssetData
+	return poll(ctx, time.Duration(10)*time.Second, func() error {
+		if !*sendData {
+			return nil
+		}
 
-		defer func() {
-			if finalErr != nil {
-				errChan <- finalErr
-			}
-		}()
+		// Fetch Data
+		code := vsCurrency.Code()
+		categoryID := category.ID()
+		coinsData, err := getTopNCoins(ctx, n, code, categoryID)
+		if err != nil {
+			return err
+		}
 
-		if *sendData {
-			// Fetch Data
-			coinsData, err := getTopNCoins(250)
-			if err != nil {
-				finalErr = err
-				return
+		// Coins ranked in the top n, before favourites and coins held
+		ranked := coinsData
+
+		// Fetch favourites and coins held outside the top n
+		fetched := make(map[string]bool, len(coinsData))
+		for _, coin := range coinsData {
+			fetched[coin.ID] = true
+		}
+
+		var inCategory map[string]bool
+		if categoryID != "" {
+			inCategory = make(map[string]bool, len(fetched))
+			for id := range fetched {
+				inCategory[id] = true
 			}
+		}
 
-			topCoinData := make([][]float64, 3)
-			topCoins := make([]string, 3)
-			maxPrices := make([]float64, 3)
-			minPrices := make([]float64, 3)
-
-			// Set Prices, Max and Min
-			for i := 0; i < 3; i++ {
-				val := coinsData[i]
-				topCoins[i] = val.Name
-				topCoinData[i] = val.SparklineIn7d.Price
-				maxPrices[i] = utils.MaxFloat64(topCoinData[i]...)
-				minPrices[i] = utils.MinFloat64(topCoinData[i]...)
-
-				// Clean data for graph
-				for index := range topCoinData[i] {
-					topCoinData[i][index] -= minPrices[i]
-				}
+		missing := []string{}
+		for id := range utils.GetFavourites() {
+			if !fetched[id] {
+				fetched[id] = true
+				missing = append(missing, id)
+			}
+		}
+		for id := range utils.GetPortfolio() {
+			if !fetched[id] {
+				fetched[id] = true
+				missing = append(missing, id)
 			}
+		}
 
-			// Aggregate data
-			data = AssetData{
-				AllCoinData: coinsData,
-				MaxPrices:   maxPrices,
-				MinPrices:   minPrices,
-				TopCoinData: topCoinData,
-				TopCoins:    topCoins,
+		if len(missing) > 0 {
+			// Sort IDs so requests are the same every run
+			sort.Strings(missing)
+			coins, err := getCoins(ctx, missing, code)
+			if err != nil {
+				return err
 			}
+			coinsData = append(coinsData, coins...)
+		}
+
+		// Graph the top 3 coins with a sparkline, which is not served for
+		// some coins. Fewer are graphed if fewer are ranked, such as in a
+		// small category.
+		topCoinData := [][]float64{}
+		topCoins := []string{}
+		maxPrices := []float64{}
+		minPrices := []float64{}
 
-			// Send Data
-			select {
-			case <-ctx.Done():
-				finalErr = ctx.Err()
-				return
-			case dataChannel <- data:
+		// Set Prices, Max and Min
+		for _, val := range ranked {
+			if len(topCoins) == 3 {
+				break
+			}
+			if len(val.Sparkline7d) == 0 {
+				continue
 			}
-		} else {
-			select {
-			case <-ctx.Done():
-				finalErr = ctx.Err()
-				return
-			default:
+
+			max := utils.MaxFloat64(val.Sparkline7d...)
+			min := utils.MinFloat64(val.Sparkline7d...)
+
+			// Clean data for graph
+			sparkline := make([]float64, len(val.Sparkline7d))
+			for index, price := range val.Sparkline7d {
+				sparkline[index] = price - min
 			}
+
+			topCoins = append(topCoins, val.Name)
+			topCoinData = append(topCoinData, sparkline)
+			maxPrices = append(maxPrices, max)
+			minPrices = append(minPrices, min)
+		}
+
+		// Aggregate data
+		data := AssetData{
+			VsCurrency:  code,
+			Category:    categoryID,
+			InCategory:  inCategory,
+			AllCoinData: coinsData,
+			Ranked:      ranked,
+			MaxPrices:   maxPrices,
+			MinPrices:   minPrices,
+			TopCoinData: topCoinData,
+			TopCoins:    topCoins,
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case dataChannel <- data:
+		}
+
+		return nil
+	}, func(err error) {
+		// Report failure, last data sent is kept on screen
+		select {
+		case <-ctx.Done():
+		case dataChannel <- AssetData{Err: err}:
 		}
 	})
 }
@@ -0,0 +1,93 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+// coinsWithIDs returns coins with the given IDs
+func coinsWithIDs(ids ...string) []CoinMarket {
+	coins := []CoinMarket{}
+	for _, id := range ids {
+		coins = append(coins, CoinMarket{ID: id})
+	}
+	return coins
+}
+
+// idsOf returns the IDs of coins
+func idsOf(coins []CoinMarket) []string {
+	ids := []string{}
+	for _, coin := range coins {
+		ids = append(ids, coin.ID)
+	}
+	return ids
+}
+
+func TestMergeCoins(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     int
+		pages [][]CoinMarket
+		want  []string
+	}{
+		{
+			name: "no pages",
+			n:    10,
+			want: []string{},
+		},
+		{
+			name:  "pages in order",
+			n:     4,
+			pages: [][]CoinMarket{coinsWithIDs("a", "b"), coinsWithIDs("c", "d")},
+			want:  []string{"a", "b", "c", "d"},
+		},
+		{
+			name:  "coin shifted to the next page",
+			n:     4,
+			pages: [][]CoinMarket{coinsWithIDs("a", "b"), coinsWithIDs("b", "c")},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "at most n coins",
+			n:     3,
+			pages: [][]CoinMarket{coinsWithIDs("a", "b"), coinsWithIDs("c", "d")},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "duplicates do not count towards n",
+			n:     3,
+			pages: [][]CoinMarket{coinsWithIDs("a", "b"), coinsWithIDs("b", "c", "d")},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "empty page",
+			n:     3,
+			pages: [][]CoinMarket{coinsWithIDs("a"), nil, coinsWithIDs("b")},
+			want:  []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idsOf(mergeCoins(tt.n, tt.pages)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -19,135 +19,268 @@ package api
 import (
 	"context"
 	"fmt"
-	"strings"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Gituser143/cryptgo/pkg/utils"
-	"github.com/gorilla/websocket"
-	gecko "github.com/superoo7/go-gecko/v3"
-	geckoTypes "github.com/superoo7/go-gecko/v3/types"
 )
 
-// GetFavouritePrices gets coin prices for coins specified by favourites.
-// This data is returned on the dataChannel.
-func GetFavouritePrices(ctx context.Context, favourites map[string]bool, dataChannel chan CoinData) error {
-
-	// Init Client
-	geckoClient := gecko.NewClient(nil)
+// GetFavouritePrices gets coin prices for coins specified by favourites, in
+// the currency currently set in vsCurrency. This data is returned on the
+// dataChannel.
+func GetFavouritePrices(ctx context.Context, favourites map[string]bool, vsCurrency *Currency, dataChannel chan CoinData) error {
 
 	// Set Parameters
-	vsCurrency := "usd"
-	order := geckoTypes.OrderTypeObject.MarketCapDesc
 	page := 1
-	sparkline := true
-	priceChangePercentage := []string{}
-
-	return utils.LoopTick(ctx, time.Duration(10)*time.Second, func(errChan chan error) {
-
-		var finalErr error
 
+	return poll(ctx, time.Duration(10)*time.Second, func() error {
 		favouriteData := make(map[string]float64)
 
-		defer func() {
-			if finalErr != nil {
-				errChan <- finalErr
-			}
-		}()
-
-		// Get Coin IDs
+		// Get Coin IDs, sorted so requests are the same every run
 		IDs := []string{}
 		for id := range favourites {
 			IDs = append(IDs, id)
 		}
+		sort.Strings(IDs)
 
 		perPage := len(IDs)
 
 		// Fetch Data
-		coinDataPointer, err := geckoClient.CoinsMarket(vsCurrency, IDs, order, perPage, page, sparkline, priceChangePercentage)
+		code := vsCurrency.Code()
+		coins, err := CurrentProvider().Markets(ctx, code, IDs, perPage, page)
 		if err != nil {
-			finalErr = err
-			return
+			return err
 		}
 
 		// Set Prices
-		for _, val := range *coinDataPointer {
-			symbol := strings.ToUpper(val.Symbol)
-			favouriteData[symbol] = val.CurrentPrice
+		for _, val := range coins {
+			favouriteData[val.ID] = val.CurrentPrice
 		}
 
 		// Aggregate data
 		coinData := CoinData{
 			Type:       "FAVOURITES",
+			VsCurrency: code,
 			Favourites: favouriteData,
 		}
 
 		// Send data
 		select {
 		case <-ctx.Done():
-			finalErr = ctx.Err()
-			return
+			return ctx.Err()
 		case dataChannel <- coinData:
 		}
 
+		return nil
+	}, func(err error) {
+		select {
+		case <-ctx.Done():
+		case dataChannel <- CoinData{Type: "FAVOURITES", Err: err}:
+		}
 	})
 }
 
-// GetCoinHistory gets price history of a coin specified by id, for an interval
-// received through the interval channel.
-// The default interval is set as 24 Hours
-func GetCoinHistory(ctx context.Context, id string, intervalChannel chan string, dataChannel chan CoinData) error {
-
-	intervalToDuration := map[string]string{
-		"24hr": "1",
-		"7d":   "7",
-		"14d":  "14",
-		"30d":  "30",
-		"90d":  "90",
-		"180d": "180",
-		"1yr":  "365",
-		"5yr":  "1825",
+// intervalDays maps keys of history intervals to the days of history served
+// by a Provider
+var intervalDays = map[string]string{
+	"24hr": "1",
+	"7d":   "7",
+	"14d":  "14",
+	"30d":  "30",
+	"90d":  "90",
+	"180d": "180",
+	"1yr":  "365",
+	"5yr":  "1825",
+	"max":  "max",
+}
+
+// historyMaxAge returns how long history selected by options is served
+// before it is fetched again, which is as long as the step between its
+// prices. Prices over a day are served every few minutes, over up to 90 days
+// hourly and over longer periods daily.
+func historyMaxAge(options HistoryOptions) time.Duration {
+	var span time.Duration
+	switch {
+	case !options.From.IsZero() && !options.To.IsZero():
+		span = options.To.Sub(options.From)
+	case !options.From.IsZero():
+		span = time.Since(options.From)
+	default:
+		days, err := strconv.Atoi(intervalDays[options.Interval])
+		if err != nil {
+			// The full history
+			return 24 * time.Hour
+		}
+		span = time.Duration(days) * 24 * time.Hour
 	}
 
-	// Set Default Interval to 1 day
-	i := "24hr"
+	switch {
+	case span <= 24*time.Hour:
+		return 5 * time.Minute
+	case span <= 90*24*time.Hour:
+		return time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
 
-	// Init Client
-	geckoClient := gecko.NewClient(nil)
+// rangeCandles is the number of candles prices of a range are merged into
+const rangeCandles = 120
 
-	return utils.LoopTick(ctx, time.Duration(3)*time.Second, func(errChan chan error) {
-		var finalErr error
+var (
+	defaultHistoryMutex sync.RWMutex
 
-		defer func() {
-			if finalErr != nil {
-				errChan <- finalErr
-			}
-		}()
+	// defaultHistory holds the history first served for a coin
+	defaultHistory = HistoryOptions{Interval: "24hr"}
+)
 
-		select {
-		case <-ctx.Done():
-			finalErr = ctx.Err()
-			return
-		case interval := <-intervalChannel:
-			// Update interval
-			i = interval
-		default:
-			break
+// SetDefaultHistory sets the history first served for a coin, such as a
+// range given on the command line
+func SetDefaultHistory(options HistoryOptions) {
+	defaultHistoryMutex.Lock()
+	defer defaultHistoryMutex.Unlock()
+
+	defaultHistory = options
+}
+
+// DefaultHistory returns the history first served for a coin
+func DefaultHistory() HistoryOptions {
+	defaultHistoryMutex.RLock()
+	defer defaultHistoryMutex.RUnlock()
+
+	return defaultHistory
+}
+
+// toCandles merges prices, at the given times, into at most n candles of
+// equally many prices
+func toCandles(times []time.Time, prices []float64, n int) []OHLC {
+	size := (len(prices) + n - 1) / n
+	candles := []OHLC{}
+	for start := 0; start < len(prices) && start < len(times); start += size {
+		end := start + size
+		if end > len(prices) {
+			end = len(prices)
 		}
 
-		// Get interval duration and fetch data
-		intervalDuration := intervalToDuration[i]
-		data, err := geckoClient.CoinsIDMarketChart(id, "usd", intervalDuration)
-		if err != nil {
-			finalErr = err
-			return
+		candles = append(candles, OHLC{
+			Time:  times[start],
+			Open:  prices[start],
+			High:  utils.MaxFloat64(prices[start:end]...),
+			Low:   utils.MinFloat64(prices[start:end]...),
+			Close: prices[end-1],
+		})
+	}
+
+	return candles
+}
+
+// GetCoinHistory gets price history of a coin specified by id, in the
+// currency currently set in vsCurrency. The interval or range, and whether
+// OHLC candles are fetched too, are received through the history channel.
+// The default history is served until then. Candles of a range are merged
+// from its prices, as no endpoint serves them. New options are served within
+// seconds, while history is only fetched again once historyMaxAge passes.
+func GetCoinHistory(ctx context.Context, id string, vsCurrency *Currency, historyChannel chan HistoryOptions, dataChannel chan CoinData) error {
+
+	options := DefaultHistory()
+	received := true // whether options were received since last served
+	var m sync.Mutex
+
+	// History of a range which has ended does not change, so it is fetched
+	// once and served again
+	var ended MarketChart
+	endedKey := ""
+
+	// Currency history was last served in and when
+	servedCode := ""
+	servedAt := time.Time{}
+
+	// Update options when new ones are received
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case newOptions := <-historyChannel:
+				m.Lock()
+				options = newOptions
+				received = true
+				m.Unlock()
+			}
 		}
+	}()
+
+	return poll(ctx, time.Duration(3)*time.Second, func() error {
+		// Serve new options at once, refetch served history once it is old
+		m.Lock()
+		current, changed := options, received
+		m.Unlock()
 
-		// Aggregate price history
-		price := []float64{}
-		for _, v := range *data.Prices {
-			price = append(price, float64(v[1]))
+		code := vsCurrency.Code()
+		if !changed && code == servedCode && time.Since(servedAt) < historyMaxAge(current) {
+			return nil
 		}
 
+		intervalDuration := intervalDays[current.Interval]
+		candles := current.Candles
+		from, to := current.From, current.To
+		var data MarketChart
+		var ohlc []OHLC
+		var err error
+		if !from.IsZero() {
+			// Fetch range, unless it has ended and was fetched already
+			key := fmt.Sprintf("%s %d %d", code, from.Unix(), to.Unix())
+			if key == endedKey {
+				data = ended
+			} else {
+				provider, ok := CurrentProvider().(MarketChartRangeProvider)
+				if !ok {
+					return unsupported("histories between dates")
+				}
+
+				data, err = provider.MarketChartRange(ctx, id, code, from, to)
+				if err != nil {
+					return err
+				}
+				if !to.IsZero() && to.Before(time.Now()) {
+					ended, endedKey = data, key
+				}
+			}
+
+			if len(data.Prices) == 0 {
+				return fmt.Errorf("no price history from %s", utils.FormatDateRange(from, to))
+			}
+
+			// Copy prices, which are cleaned for graphs below
+			data.Prices = append([]float64{}, data.Prices...)
+			if candles {
+				ohlc = toCandles(data.Times, data.Prices, rangeCandles)
+			}
+		} else {
+			data, err = CurrentProvider().MarketChart(ctx, id, code, intervalDuration)
+			if err != nil {
+				return err
+			}
+
+			if len(data.Prices) == 0 {
+				return fmt.Errorf("no price history over %s", current.Interval)
+			}
+
+			// Candles are left out if the current provider does not serve
+			// them
+			if provider, ok := CurrentProvider().(OHLCProvider); ok && candles {
+				ohlc, err = provider.OHLC(ctx, id, code, intervalDuration)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// Aggregate price, volume and market cap history
+		price := data.Prices
+
 		// Set max and min
 		min := utils.MinFloat64(price...)
 		max := utils.MaxFloat64(price...)
@@ -159,189 +292,114 @@ func GetCoinHistory(ctx context.Context, id string, intervalChannel chan string,
 
 		// Aggregate data
 		coinData := CoinData{
-			Type:         "HISTORY",
-			PriceHistory: price,
-			MinPrice:     min,
-			MaxPrice:     max,
+			Type:             "HISTORY",
+			VsCurrency:       code,
+			PriceHistory:     price,
+			VolumeHistory:    data.TotalVolumes,
+			MarketCapHistory: data.MarketCaps,
+			OHLC:             ohlc,
+			MinPrice:         min,
+			MaxPrice:         max,
 		}
 
 		// Send Data
 		select {
 		case <-ctx.Done():
-			finalErr = ctx.Err()
-			return
+			return ctx.Err()
 		case dataChannel <- coinData:
 		}
+
+		servedCode, servedAt = code, time.Now()
+		m.Lock()
+		if options == current {
+			received = false
+		}
+		m.Unlock()
+		return nil
+	}, func(err error) {
+		select {
+		case <-ctx.Done():
+		case dataChannel <- CoinData{Type: "HISTORY", Err: err}:
+		}
 	})
 }
 
-// GetCoinDetails fetches details for a coin specified by id
-// and sends the data on dataChannel
-func GetCoinDetails(ctx context.Context, id string, dataChannel chan CoinData) error {
-	// Init client
-	geckoClient := gecko.NewClient(nil)
-
-	// Set Parameters
-	localization := false
-	tickers := false
-	marketData := true
-	communityData := false
-	developerData := false
-	sparkline := false
-
-	return utils.LoopTick(ctx, time.Duration(10)*time.Second, func(errChan chan error) {
-		var finalErr error
-
-		defer func() {
-			if finalErr != nil {
-				errChan <- finalErr
-			}
-		}()
-
+// GetCoinDetails fetches details for a coin specified by id, in the
+// currency currently set in vsCurrency, and sends the data on dataChannel.
+// Activity of the coin is recorded, and sent along with the activity of a
+// day or more earlier if it was recorded then.
+func GetCoinDetails(ctx context.Context, id string, vsCurrency *Currency, dataChannel chan CoinData) error {
+	return poll(ctx, time.Duration(10)*time.Second, func() error {
 		// Fetch Data
-		coinData, err := geckoClient.CoinsID(id, localization, tickers, marketData, communityData, developerData, sparkline)
+		code := vsCurrency.Code()
+		data, err := CurrentProvider().CoinDetails(ctx, id, code)
 		if err != nil {
-			finalErr = err
-			return
+			return err
 		}
 
-		// Get Explorer links
-		explorerLinks := [][]string{}
-		for key, val := range *coinData.Links {
-			if key == "blockchain_site" {
-				sites := val.([]interface{})
-				for _, site := range sites {
-					siteStr := site.(string)
-					if siteStr != "" {
-						explorerLinks = append(explorerLinks, []string{siteStr})
-					}
-				}
-			}
-		}
+		since := recordActivity(id, ActivitySample{Time: time.Now(), Activity: data.Activity})
 
-		// Get Total Supply if coin has it
-		totalSupply := 0.0
-		if coinData.MarketData.TotalSupply != nil {
-			totalSupply = *coinData.MarketData.TotalSupply
-		}
-
-		// Get Change Percents
-		changePercents := [][]string{
-			{"24H", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage24h)},
-			{"7D", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage7d)},
-			{"14D", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage14d)},
-			{"30D", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage30d)},
-			{"60D", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage60d)},
-			{"200D", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage200d)},
-			{"1Y", fmt.Sprintf("%.2f", coinData.MarketData.PriceChangePercentage1y)},
+		// Aggregate data
+		CoinDetails := CoinData{
+			Type:          "DETAILS",
+			VsCurrency:    code,
+			Details:       data,
+			ActivitySince: since,
 		}
 
-		for i, row := range changePercents {
-			change := row[1]
-			if string(change[0]) == "-" {
-				change = utils.DownArrow + " " + change[1:]
-			} else {
-				change = utils.UpArrow + " " + change
-			}
-			changePercents[i][1] = change
+		// Send data
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case dataChannel <- CoinDetails:
 		}
 
-		// Get ATH, ATL and Last update times
-		timeLayout := "2006-01-02T15:04:05.000Z"
-		tATHDate, err := time.Parse(timeLayout, coinData.MarketData.ATHDate["usd"])
-		if err != nil {
-			finalErr = err
-			return
+		return nil
+	}, func(err error) {
+		select {
+		case <-ctx.Done():
+		case dataChannel <- CoinData{Type: "DETAILS", Err: err}:
 		}
+	})
+}
 
-		tATLDate, err := time.Parse(timeLayout, coinData.MarketData.ATLDate["usd"])
-		if err != nil {
-			finalErr = err
-			return
+// GetCoinTickers fetches the pairs a coin specified by id trades in and sends
+// them on dataChannel. Tickers change slowly and are costly to serve, so they
+// are fetched once a minute. Nothing is served if the current provider does
+// not serve tickers.
+func GetCoinTickers(ctx context.Context, id string, dataChannel chan CoinData) error {
+	return poll(ctx, time.Duration(60)*time.Second, func() error {
+		provider, ok := CurrentProvider().(TickersProvider)
+		if !ok {
+			return nil
 		}
 
-		tUpdate, err := time.Parse(timeLayout, coinData.LastUpdated)
+		// Fetch Data
+		tickers, err := provider.Tickers(ctx, id)
 		if err != nil {
-			finalErr = err
-			return
-		}
-
-		data := CoinDetails{
-			Name:           coinData.Name,
-			Symbol:         strings.ToUpper(coinData.Symbol),
-			Rank:           fmt.Sprintf("%d", coinData.MarketCapRank),
-			BlockTime:      fmt.Sprintf("%d", coinData.BlockTimeInMin),
-			MarketCap:      coinData.MarketData.MarketCap["usd"],
-			Website:        "",
-			Explorers:      explorerLinks,
-			ATH:            coinData.MarketData.ATH["usd"],
-			ATHDate:        tATHDate.Format(time.RFC822),
-			ATL:            coinData.MarketData.ATL["usd"],
-			ATLDate:        tATLDate.Format(time.RFC822),
-			High24:         coinData.MarketData.High24["usd"],
-			Low24:          coinData.MarketData.Low24["usd"],
-			TotalVolume:    coinData.MarketData.TotalVolume["usd"],
-			ChangePercents: changePercents,
-			TotalSupply:    totalSupply,
-			CurrentSupply:  coinData.MarketData.CirculatingSupply,
-			LastUpdate:     tUpdate.Format(time.RFC822),
+			return err
 		}
 
-		// Aggregate data
-		CoinDetails := CoinData{
-			Type:    "DETAILS",
-			Details: data,
+		// Aggregate data, prices and volumes are served in USD whatever the
+		// currency selected, pages convert them using currency rates
+		coinData := CoinData{
+			Type:       "TICKERS",
+			VsCurrency: "usd",
+			Tickers:    tickers,
 		}
 
 		// Send data
 		select {
 		case <-ctx.Done():
-			finalErr = ctx.Err()
-			return
-		case dataChannel <- CoinDetails:
-		}
-	})
-}
-
-// GetLivePrice uses a websocket to stream realtime prices of a coin specified
-// by id. The prices are sent on the dataChannel
-func GetLivePrice(ctx context.Context, id string, dataChannel chan string) error {
-	url := fmt.Sprintf("wss://ws.coincap.io/prices?assets=%s", id)
-	c, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
-	if err != nil {
-		return err
-	}
-	defer c.Close()
-
-	msg := make(map[string]string)
-
-	return utils.LoopTick(ctx, time.Duration(100*time.Millisecond), func(errChan chan error) {
-		var finalErr error
-
-		// Defer panic recovery for closed websocket
-		defer func() {
-			if e := recover(); e != nil {
-				finalErr = fmt.Errorf("socket read error")
-			}
-		}()
-
-		defer func() {
-			if finalErr != nil {
-				errChan <- finalErr
-			}
-		}()
-
-		err = c.ReadJSON(&msg)
-		if err != nil {
-			finalErr = err
-			return
+			return ctx.Err()
+		case dataChannel <- coinData:
 		}
 
+		return nil
+	}, func(err error) {
 		select {
 		case <-ctx.Done():
-			finalErr = ctx.Err()
-			return
-		case dataChannel <- msg[id]:
+		case dataChannel <- CoinData{Type: "TICKERS", Err: err}:
 		}
 	})
 }
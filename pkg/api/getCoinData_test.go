@@ -0,0 +1,114 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestToCandles(t *testing.T) {
+	start := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time {
+		return start.Add(time.Duration(hours) * time.Hour)
+	}
+
+	tests := []struct {
+		name   string
+		times  []time.Time
+		prices []float64
+		n      int
+		want   []OHLC
+	}{
+		{
+			name: "no prices",
+			n:    3,
+			want: []OHLC{},
+		},
+		{
+			name:   "fewer prices than candles",
+			times:  []time.Time{at(0), at(1)},
+			prices: []float64{1, 2},
+			n:      3,
+			want: []OHLC{
+				{Time: at(0), Open: 1, High: 1, Low: 1, Close: 1},
+				{Time: at(1), Open: 2, High: 2, Low: 2, Close: 2},
+			},
+		},
+		{
+			name:   "prices split evenly",
+			times:  []time.Time{at(0), at(1), at(2), at(3)},
+			prices: []float64{1, 3, 2, 0.5},
+			n:      2,
+			want: []OHLC{
+				{Time: at(0), Open: 1, High: 3, Low: 1, Close: 3},
+				{Time: at(2), Open: 2, High: 2, Low: 0.5, Close: 0.5},
+			},
+		},
+		{
+			name:   "last candle shorter",
+			times:  []time.Time{at(0), at(1), at(2), at(3), at(4)},
+			prices: []float64{5, 4, 6, 7, 8},
+			n:      2,
+			want: []OHLC{
+				{Time: at(0), Open: 5, High: 6, Low: 4, Close: 6},
+				{Time: at(3), Open: 7, High: 8, Low: 7, Close: 8},
+			},
+		},
+		{
+			name:   "fewer times than prices",
+			times:  []time.Time{at(0)},
+			prices: []float64{1, 2},
+			n:      2,
+			want: []OHLC{
+				{Time: at(0), Open: 1, High: 1, Low: 1, Close: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toCandles(tt.times, tt.prices, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryMaxAge(t *testing.T) {
+	from := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		options HistoryOptions
+		want    time.Duration
+	}{
+		{HistoryOptions{Interval: "24hr"}, 5 * time.Minute},
+		{HistoryOptions{Interval: "7d"}, time.Hour},
+		{HistoryOptions{Interval: "90d"}, time.Hour},
+		{HistoryOptions{Interval: "1yr"}, 24 * time.Hour},
+		{HistoryOptions{Interval: "max"}, 24 * time.Hour},
+		{HistoryOptions{From: from, To: from.Add(12 * time.Hour)}, 5 * time.Minute},
+		{HistoryOptions{From: from, To: from.AddDate(0, 1, 0)}, time.Hour},
+		{HistoryOptions{From: from}, 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := historyMaxAge(tt.options); got != tt.want {
+			t.Errorf("historyMaxAge(%+v) = %v, want %v", tt.options, got, tt.want)
+		}
+	}
+}
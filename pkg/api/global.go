@@ -0,0 +1,120 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// dominanceHistoryFile is the cache file dominance is recorded in
+	dominanceHistoryFile = "dominance-history.json"
+
+	// dominanceInterval is the least time between recorded samples
+	dominanceInterval = 10 * time.Minute
+
+	// dominanceRetention is how long samples are kept
+	dominanceRetention = 30 * 24 * time.Hour
+)
+
+var (
+	dominanceMutex   sync.Mutex
+	dominanceHistory []DominanceSample
+	dominanceLoaded  bool
+)
+
+// recordDominance adds sample to the dominance history, which is kept on
+// disk as no free endpoint serves it. Samples are recorded at most once per
+// dominanceInterval, but the latest sample is always last in the history
+// returned.
+func recordDominance(sample DominanceSample) []DominanceSample {
+	dominanceMutex.Lock()
+	defer dominanceMutex.Unlock()
+
+	if !dominanceLoaded {
+		readCache(dominanceHistoryFile, &dominanceHistory)
+		dominanceLoaded = true
+	}
+
+	n := len(dominanceHistory)
+	if n == 0 || sample.Time.Sub(dominanceHistory[n-1].Time) >= dominanceInterval {
+		// Drop samples older than the retention period
+		start := 0
+		for start < n && sample.Time.Sub(dominanceHistory[start].Time) > dominanceRetention {
+			start++
+		}
+		dominanceHistory = append(dominanceHistory[start:], sample)
+
+		writeCache(dominanceHistoryFile, dominanceHistory)
+
+		return append([]DominanceSample{}, dominanceHistory...)
+	}
+
+	return append(append([]DominanceSample{}, dominanceHistory...), sample)
+}
+
+// GetGlobalData serves market wide data, with totals in the currency
+// currently set in vsCurrency, on dataChannel. Nothing is served if the
+// current provider does not serve market wide data.
+func GetGlobalData(ctx context.Context, vsCurrency *Currency, dataChannel chan GlobalData) error {
+	return poll(ctx, time.Duration(60)*time.Second, func() error {
+		provider, ok := CurrentProvider().(GlobalProvider)
+		if !ok {
+			return nil
+		}
+
+		// Fetch Data
+		code := vsCurrency.Code()
+		global, err := provider.Global(ctx)
+		if err != nil {
+			return err
+		}
+
+		history := recordDominance(DominanceSample{
+			Time: time.Now(),
+			BTC:  global.Dominance["btc"],
+			ETH:  global.Dominance["eth"],
+		})
+
+		// Aggregate data
+		data := GlobalData{
+			VsCurrency:         code,
+			TotalMarketCap:     global.TotalMarketCap[code],
+			TotalVolume:        global.TotalVolume[code],
+			MarketCapChange24h: global.MarketCapChange24h,
+			ActiveCoins:        global.ActiveCoins,
+			Markets:            global.Markets,
+			DominanceHistory:   history,
+		}
+
+		// Send data
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case dataChannel <- data:
+		}
+
+		return nil
+	}, func(err error) {
+		select {
+		case <-ctx.Done():
+		case dataChannel <- GlobalData{Err: err}:
+		}
+	})
+}
@@ -0,0 +1,257 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// resubscribeDelay is how long a PriceHub waits after the set of coins
+// changes before reconnecting, so scrolling through a table does not open a
+// connection per row
+const resubscribeDelay = time.Second
+
+// PriceHub streams live prices of many coins over a single connection to the
+// current provider and fans them out to any number of subscribers. Pages
+// declare the coins they show with SetAssets. The connection is reopened
+// whenever that set of coins changes, and with backoff whenever it drops.
+type PriceHub struct {
+	mutex       sync.Mutex
+	assets      map[string][]string // coin IDs, by the page asking for them
+	subscribers map[*PriceSubscription]bool
+	changed     chan struct{}
+}
+
+// PriceSubscription receives live prices from a PriceHub. C is signalled when
+// new prices are available, which are then collected with Prices. Updates are
+// merged while they are not collected, so a busy subscriber never blocks the
+// hub or other subscribers.
+type PriceSubscription struct {
+	C <-chan struct{}
+
+	ready  chan struct{}
+	mutex  sync.Mutex
+	prices map[string]float64
+}
+
+// NewPriceHub returns an empty PriceHub, which streams prices once Run is
+// called
+func NewPriceHub() *PriceHub {
+	return &PriceHub{
+		assets:      make(map[string][]string),
+		subscribers: make(map[*PriceSubscription]bool),
+		changed:     make(chan struct{}, 1),
+	}
+}
+
+// SetAssets sets the IDs of coins whose prices are needed by owner,
+// replacing those it set before. Passing no IDs removes the owner.
+func (h *PriceHub) SetAssets(owner string, ids []string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	before := h.ids()
+
+	if len(ids) == 0 {
+		delete(h.assets, owner)
+	} else {
+		h.assets[owner] = append([]string{}, ids...)
+	}
+
+	if !equalStrings(before, h.ids()) {
+		select {
+		case h.changed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ids returns the sorted IDs of all coins asked for, the hub must be locked
+func (h *PriceHub) ids() []string {
+	set := make(map[string]bool)
+	for _, ids := range h.assets {
+		for _, id := range ids {
+			if id != "" {
+				set[id] = true
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// Subscribe returns a PriceSubscription receiving all prices streamed by the
+// hub until it is passed to Unsubscribe
+func (h *PriceHub) Subscribe() *PriceSubscription {
+	ready := make(chan struct{}, 1)
+	s := &PriceSubscription{
+		C:      ready,
+		ready:  ready,
+		prices: make(map[string]float64),
+	}
+
+	h.mutex.Lock()
+	h.subscribers[s] = true
+	h.mutex.Unlock()
+
+	return s
+}
+
+// Unsubscribe stops prices from being sent to s
+func (h *PriceHub) Unsubscribe(s *PriceSubscription) {
+	h.mutex.Lock()
+	delete(h.subscribers, s)
+	h.mutex.Unlock()
+}
+
+// publish sends prices to all subscribers
+func (h *PriceHub) publish(prices map[string]float64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for s := range h.subscribers {
+		s.mutex.Lock()
+		for id, price := range prices {
+			s.prices[id] = price
+		}
+		s.mutex.Unlock()
+
+		select {
+		case s.ready <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Prices returns prices in USD, by coin ID, received since it was last
+// called
+func (s *PriceSubscription) Prices() map[string]float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	prices := s.prices
+	s.prices = make(map[string]float64)
+
+	return prices
+}
+
+// Run streams prices of the coins asked for until the context is cancelled.
+// No prices are streamed if the current provider does not stream them.
+func (h *PriceHub) Run(ctx context.Context) error {
+	provider, ok := CurrentProvider().(LivePricesProvider)
+	if !ok {
+		return nil
+	}
+
+	failures := 0
+
+	for {
+		h.mutex.Lock()
+		ids := h.ids()
+		h.mutex.Unlock()
+
+		// Wait for coins to be asked for
+		if len(ids) == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-h.changed:
+			}
+			continue
+		}
+
+		connCtx, cancel := context.WithCancel(ctx)
+		dataChannel := make(chan map[string]float64)
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- provider.LivePrices(connCtx, ids, dataChannel)
+		}()
+
+		// Publish prices until the connection drops or coins change
+		var err error
+		var resubscribe <-chan time.Time
+	stream:
+		for {
+			select {
+			case <-ctx.Done():
+				cancel()
+				<-errChan
+				return ctx.Err()
+
+			case prices := <-dataChannel:
+				failures = 0
+				h.publish(prices)
+
+			case <-h.changed:
+				if resubscribe == nil {
+					resubscribe = time.After(resubscribeDelay)
+				}
+
+			case <-resubscribe:
+				cancel()
+				<-errChan
+				break stream
+
+			case err = <-errChan:
+				cancel()
+				break stream
+			}
+		}
+
+		if err == nil {
+			continue
+		}
+
+		// Reconnect with backoff, sooner if the coins asked for change
+		failures++
+		delay := backoff(failures, err)
+		if !IsRetryable(err) {
+			delay = jitter(maxBackoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-h.changed:
+		case <-time.After(delay):
+		}
+	}
+}
+
+// equalStrings reports whether a and b hold the same strings in order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
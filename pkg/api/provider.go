@@ -0,0 +1,189 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider is implemented by sources of market data. All fetchers in this
+// package go through the current provider, which defaults to CoinGecko (with
+// CoinCap for live prices and currency rates). Providers need only serve
+// these core methods, data beyond them is served by providers which also
+// implement the interfaces below, and left out or reported as unsupported
+// otherwise. Requests made by all methods are abandoned once ctx is
+// cancelled.
+type Provider interface {
+	// Markets returns market data of coins ordered by market cap, priced in
+	// vsCurrency. If ids is not empty, only the given coins are returned.
+	Markets(ctx context.Context, vsCurrency string, ids []string, perPage, page int) ([]CoinMarket, error)
+
+	// MarketChart returns the history of a coin over the given number of days
+	MarketChart(ctx context.Context, id, vsCurrency, days string) (MarketChart, error)
+
+	// CoinDetails returns details of the coin specified by id, priced in
+	// vsCurrency
+	CoinDetails(ctx context.Context, id, vsCurrency string) (CoinDetails, error)
+
+	// CoinIDs returns the IDs of all coins known to the provider
+	CoinIDs(ctx context.Context) ([]CoinID, error)
+}
+
+// CategoriesProvider is implemented by providers grouping coins in
+// categories
+type CategoriesProvider interface {
+	// Categories returns categories of coins with their aggregate market
+	// data, ordered by market cap
+	Categories(ctx context.Context) ([]CategoryMarket, error)
+
+	// CategoryMarkets returns market data of coins in the category specified
+	// by its ID, ordered by market cap and priced in vsCurrency
+	CategoryMarkets(ctx context.Context, category, vsCurrency string, perPage, page int) ([]CoinMarket, error)
+}
+
+// MarketChartRangeProvider is implemented by providers serving the history
+// of a coin between any two times
+type MarketChartRangeProvider interface {
+	// MarketChartRange returns the history of a coin between from and to,
+	// with the times of prices. A zero to returns history up to now.
+	MarketChartRange(ctx context.Context, id, vsCurrency string, from, to time.Time) (MarketChart, error)
+}
+
+// OHLCProvider is implemented by providers serving candles
+type OHLCProvider interface {
+	// OHLC returns open, high, low and close prices of a coin over the given
+	// number of days, oldest first
+	OHLC(ctx context.Context, id, vsCurrency, days string) ([]OHLC, error)
+}
+
+// TickersProvider is implemented by providers serving the markets a coin
+// trades in
+type TickersProvider interface {
+	// Tickers returns the pairs the coin specified by id trades in, ordered
+	// by volume
+	Tickers(ctx context.Context, id string) ([]Ticker, error)
+}
+
+// SearchProvider is implemented by providers searching all coins
+type SearchProvider interface {
+	// Search returns coins whose name or symbol match query, best matches
+	// first
+	Search(ctx context.Context, query string) ([]SearchResult, error)
+}
+
+// TrendingProvider is implemented by providers serving coins trending in
+// searches
+type TrendingProvider interface {
+	// Trending returns coins trending in searches, most trending first
+	Trending(ctx context.Context) ([]TrendingCoin, error)
+}
+
+// GlobalProvider is implemented by providers serving market wide data
+type GlobalProvider interface {
+	// Global returns market wide data such as the total market cap
+	Global(ctx context.Context) (GlobalMarket, error)
+}
+
+// LivePricesProvider is implemented by providers streaming prices
+type LivePricesProvider interface {
+	// LivePrices streams realtime prices of the coins specified by ids on
+	// dataChannel until the context is cancelled or the stream breaks. Each
+	// update maps IDs of coins whose price changed to their price in USD.
+	LivePrices(ctx context.Context, ids []string, dataChannel chan map[string]float64) error
+}
+
+// VsCurrenciesProvider is implemented by providers pricing coins in
+// currencies other than USD
+type VsCurrenciesProvider interface {
+	// VsCurrencies returns the lower case codes of currencies prices can be
+	// fetched in, such as "usd", "eur" or "btc"
+	VsCurrencies(ctx context.Context) ([]string, error)
+}
+
+// CurrencyRatesProvider is implemented by providers serving rates of
+// currencies
+type CurrencyRatesProvider interface {
+	// CurrencyRates returns the USD rates of supported fiat and crypto currencies
+	CurrencyRates(ctx context.Context) ([]CurrencyRate, error)
+}
+
+// unsupported returns the error met when the current provider does not serve
+// what, such as "categories"
+func unsupported(what string) error {
+	return fmt.Errorf("%s are %w", what, ErrUnsupported)
+}
+
+var (
+	providerMutex sync.RWMutex
+
+	// providers maps names to constructors of available providers
+	providers = map[string]func() Provider{
+		"coingecko": func() Provider { return NewCoinGeckoProvider() },
+	}
+
+	// provider is used by all fetchers
+	provider Provider = NewCoinGeckoProvider()
+)
+
+// RegisterProvider makes a provider available under the given name, to be
+// selected later with UseProvider
+func RegisterProvider(name string, newProvider func() Provider) {
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+
+	providers[strings.ToLower(name)] = newProvider
+}
+
+// UseProvider sets the provider registered under name as the current provider
+func UseProvider(name string) error {
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+
+	newProvider, ok := providers[strings.ToLower(name)]
+	if !ok {
+		names := []string{}
+		for n := range providers {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown provider %q, available providers: %s", name, strings.Join(names, ", "))
+	}
+
+	provider = newProvider()
+	return nil
+}
+
+// SetProvider sets p as the provider used by all fetchers
+func SetProvider(p Provider) {
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+
+	provider = p
+}
+
+// CurrentProvider returns the provider used by all fetchers
+func CurrentProvider() Provider {
+	providerMutex.RLock()
+	defer providerMutex.RUnlock()
+
+	return provider
+}
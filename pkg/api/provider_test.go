@@ -0,0 +1,238 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeProvider serves ranked coins and implements only the core Provider
+// methods
+type fakeProvider struct {
+	coins      []CoinMarket
+	coinIDs    []CoinID
+	coinIDsErr error
+
+	mutex    sync.Mutex
+	requests []string
+}
+
+// newFakeProvider returns a fakeProvider serving n coins, ranked by their
+// IDs "coin-1" onwards
+func newFakeProvider(n int) *fakeProvider {
+	p := &fakeProvider{}
+	for i := 1; i <= n; i++ {
+		p.coins = append(p.coins, CoinMarket{ID: fmt.Sprintf("coin-%d", i), MarketCapRank: i})
+	}
+	return p
+}
+
+func (p *fakeProvider) Markets(ctx context.Context, vsCurrency string, ids []string, perPage, page int) ([]CoinMarket, error) {
+	p.mutex.Lock()
+	p.requests = append(p.requests, fmt.Sprintf("%s %d %d %d", vsCurrency, len(ids), perPage, page))
+	p.mutex.Unlock()
+
+	coins := p.coins
+	if len(ids) > 0 {
+		wanted := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			wanted[id] = true
+		}
+
+		coins = []CoinMarket{}
+		for _, coin := range p.coins {
+			if wanted[coin.ID] {
+				coins = append(coins, coin)
+			}
+		}
+	}
+
+	start := (page - 1) * perPage
+	if start >= len(coins) {
+		return []CoinMarket{}, nil
+	}
+	end := start + perPage
+	if end > len(coins) {
+		end = len(coins)
+	}
+	return coins[start:end], nil
+}
+
+func (p *fakeProvider) MarketChart(ctx context.Context, id, vsCurrency, days string) (MarketChart, error) {
+	return MarketChart{}, nil
+}
+
+func (p *fakeProvider) CoinDetails(ctx context.Context, id, vsCurrency string) (CoinDetails, error) {
+	return CoinDetails{}, nil
+}
+
+func (p *fakeProvider) CoinIDs(ctx context.Context) ([]CoinID, error) {
+	return p.coinIDs, p.coinIDsErr
+}
+
+// useFakeProvider sets p as the current provider until the test ends
+func useFakeProvider(t *testing.T, p Provider) {
+	previous := CurrentProvider()
+	SetProvider(p)
+	t.Cleanup(func() {
+		SetProvider(previous)
+	})
+}
+
+func TestGetTopNCoins(t *testing.T) {
+	tests := []struct {
+		n        int
+		coins    int
+		want     int
+		requests []string
+	}{
+		{10, 400, 10, []string{"usd 0 10 1"}},
+		{250, 400, 250, []string{"usd 0 250 1"}},
+		{300, 400, 300, []string{"usd 0 250 1", "usd 0 250 2"}},
+		{600, 400, 400, []string{"usd 0 250 1", "usd 0 250 2", "usd 0 250 3"}},
+	}
+
+	for _, tt := range tests {
+		p := newFakeProvider(tt.coins)
+		useFakeProvider(t, p)
+
+		coins, err := getTopNCoins(context.Background(), tt.n, "usd", "")
+		if err != nil {
+			t.Fatalf("getTopNCoins(%d): %v", tt.n, err)
+		}
+
+		if len(coins) != tt.want {
+			t.Errorf("getTopNCoins(%d) returned %d coins, want %d", tt.n, len(coins), tt.want)
+		}
+		for i, coin := range coins {
+			if coin.MarketCapRank != i+1 {
+				t.Errorf("getTopNCoins(%d): coin %d has rank %d", tt.n, i, coin.MarketCapRank)
+				break
+			}
+		}
+
+		// Pages are fetched concurrently, in any order
+		requests := map[string]bool{}
+		for _, request := range p.requests {
+			requests[request] = true
+		}
+		for _, request := range tt.requests {
+			if !requests[request] {
+				t.Errorf("getTopNCoins(%d) made requests %v, want %v", tt.n, p.requests, tt.requests)
+				break
+			}
+		}
+	}
+}
+
+func TestGetCoins(t *testing.T) {
+	p := newFakeProvider(600)
+	useFakeProvider(t, p)
+
+	ids := []string{}
+	for i := 600; i > 300; i-- {
+		ids = append(ids, fmt.Sprintf("coin-%d", i))
+	}
+
+	coins, err := getCoins(context.Background(), ids, "eur")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coins) != len(ids) {
+		t.Errorf("got %d coins, want %d", len(coins), len(ids))
+	}
+	if len(p.requests) != 2 {
+		t.Errorf("made requests %v, want 2 pages", p.requests)
+	}
+}
+
+func TestGetHoldings(t *testing.T) {
+	useFakeProvider(t, newFakeProvider(400))
+
+	ids := []string{}
+	for i := 1; i <= 300; i++ {
+		ids = append(ids, fmt.Sprintf("coin-%d", i))
+	}
+
+	vsCurrency := NewCurrency()
+	dataChannel := make(chan CoinData, 1)
+	if err := GetHoldings(context.Background(), ids, vsCurrency, dataChannel); err != nil {
+		t.Fatal(err)
+	}
+
+	// Holdings are fetched past a single page
+	data := <-dataChannel
+	if data.Type != "PORTFOLIO" || data.Err != nil || data.VsCurrency != vsCurrency.Code() || len(data.Holdings) != len(ids) {
+		t.Errorf("got %s data in %s with %d holdings and error %v, want %d holdings", data.Type, data.VsCurrency, len(data.Holdings), data.Err, len(ids))
+	}
+}
+
+func TestMissingCapabilities(t *testing.T) {
+	useFakeProvider(t, newFakeProvider(10))
+	ctx := context.Background()
+
+	if _, err := getTopNCoins(ctx, 10, "usd", "layer-1"); !errors.Is(err, ErrUnsupported) || !strings.Contains(err.Error(), "categories") {
+		t.Errorf("getTopNCoins in a category: got error %v, want categories to be unsupported", err)
+	}
+
+	if _, err := SearchCoins(ctx, "fake provider query"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SearchCoins: got error %v, want search to be unsupported", err)
+	}
+
+	codes, err := fetchVsCurrencies(ctx)
+	if err != nil || !reflect.DeepEqual(codes, []string{"usd"}) {
+		t.Errorf("fetchVsCurrencies() = %v, %v, want only usd", codes, err)
+	}
+
+	// Live prices are not streamed, without waiting for coins
+	hub := NewPriceHub()
+	hub.SetAssets("test", []string{"coin-1"})
+	if err := hub.Run(ctx); err != nil {
+		t.Errorf("PriceHub.Run: %v", err)
+	}
+}
+
+func TestUseProvider(t *testing.T) {
+	useFakeProvider(t, CurrentProvider())
+
+	p := newFakeProvider(1)
+	RegisterProvider("Fake", func() Provider { return p })
+	t.Cleanup(func() {
+		providerMutex.Lock()
+		defer providerMutex.Unlock()
+
+		delete(providers, "fake")
+	})
+
+	if err := UseProvider("FAKE"); err != nil {
+		t.Fatal(err)
+	}
+	if CurrentProvider() != Provider(p) {
+		t.Error("fake provider is not the current provider")
+	}
+
+	err := UseProvider("unknown")
+	if err == nil || !strings.Contains(err.Error(), "coingecko, fake") {
+		t.Errorf("UseProvider(unknown): got error %v, want available providers listed", err)
+	}
+}
@@ -0,0 +1,439 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// trafficFile is the name of the file API traffic is recorded to
+const trafficFile = "traffic.jsonl"
+
+// trafficEntry holds a single recorded HTTP response or websocket event
+type trafficEntry struct {
+	Kind    string        `json:"kind"`   // "http", "dial" or "frame"
+	Offset  time.Duration `json:"offset"` // time since recording started
+	Latency time.Duration `json:"latency,omitempty"`
+	Method  string        `json:"method,omitempty"`
+	URL     string        `json:"url"`
+	Conn    int           `json:"conn,omitempty"` // websocket connection number
+	Status  int           `json:"status,omitempty"`
+	Header  http.Header   `json:"header,omitempty"`
+	Body    []byte        `json:"body,omitempty"`
+}
+
+// wsConn is a websocket connection which can be recorded and replayed
+type wsConn interface {
+	ReadMessage() (int, []byte, error)
+	Close() error
+}
+
+var (
+	trafficMutex sync.RWMutex
+
+	// transport is used by all API requests, it is swapped while recording
+	// or replaying traffic
+	transport http.RoundTripper = networkTransport{}
+
+	// dialWebsocket is used to open all websocket connections
+	dialWebsocket = defaultDialWebsocket
+
+	// activeRecorder saves traffic while recording, until StopRecording is
+	// called
+	activeRecorder *recorder
+)
+
+// trafficTransport routes requests through the current transport
+type trafficTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (trafficTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trafficMutex.RLock()
+	t := transport
+	trafficMutex.RUnlock()
+
+	return t.RoundTrip(req)
+}
+
+// dial opens a websocket connection through the current dialer
+func dial(ctx context.Context, url string) (wsConn, error) {
+	trafficMutex.RLock()
+	d := dialWebsocket
+	trafficMutex.RUnlock()
+
+	return d(ctx, url)
+}
+
+// replaying reports whether recorded traffic is served instead of making
+// network requests
+func replaying() bool {
+	trafficMutex.RLock()
+	defer trafficMutex.RUnlock()
+
+	_, ok := transport.(*replayTransport)
+	return ok
+}
+
+// recorder writes traffic entries to disk
+type recorder struct {
+	mutex  sync.Mutex
+	file   *os.File
+	start  time.Time
+	conns  int
+	closed bool
+
+	// Transport and dialer replaced while recording
+	transport     http.RoundTripper
+	dialWebsocket func(ctx context.Context, url string) (wsConn, error)
+}
+
+func (r *recorder) write(entry trafficEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Traffic still in flight once recording stops is dropped
+	if r.closed {
+		return
+	}
+	r.file.Write(append(data, '\n'))
+}
+
+// close flushes recorded traffic to disk and closes the file
+func (r *recorder) close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	if err := r.file.Sync(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+// Record saves every API response and websocket frame to a file in dir, until
+// StopRecording is called
+func Record(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(dir, trafficFile))
+	if err != nil {
+		return err
+	}
+
+	trafficMutex.Lock()
+	defer trafficMutex.Unlock()
+
+	r := &recorder{
+		file:          file,
+		start:         time.Now(),
+		transport:     transport,
+		dialWebsocket: dialWebsocket,
+	}
+	activeRecorder = r
+
+	transport = &recordTransport{
+		next:     transport,
+		recorder: r,
+	}
+
+	next := dialWebsocket
+	dialWebsocket = func(ctx context.Context, url string) (wsConn, error) {
+		dialTime := time.Now()
+		c, err := next(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+
+		r.mutex.Lock()
+		r.conns++
+		conn := r.conns
+		r.mutex.Unlock()
+
+		r.write(trafficEntry{
+			Kind:   "dial",
+			Offset: dialTime.Sub(r.start),
+			URL:    url,
+			Conn:   conn,
+		})
+
+		return &recordConn{wsConn: c, url: url, conn: conn, recorder: r}, nil
+	}
+
+	return nil
+}
+
+// StopRecording stops recording traffic and makes sure all traffic recorded
+// is saved to disk. It does nothing if traffic is not being recorded.
+func StopRecording() error {
+	trafficMutex.Lock()
+	r := activeRecorder
+	if r != nil {
+		transport = r.transport
+		dialWebsocket = r.dialWebsocket
+		activeRecorder = nil
+	}
+	trafficMutex.Unlock()
+
+	if r == nil {
+		return nil
+	}
+
+	if err := r.close(); err != nil {
+		return fmt.Errorf("failed to save recorded traffic: %v", err)
+	}
+	return nil
+}
+
+// recordTransport saves responses received from the next transport
+type recordTransport struct {
+	next     http.RoundTripper
+	recorder *recorder
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read body so it can be saved and served again
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.recorder.write(trafficEntry{
+		Kind:    "http",
+		Offset:  start.Sub(t.recorder.start),
+		Latency: time.Since(start),
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Status:  res.StatusCode,
+		Header:  res.Header,
+		Body:    body,
+	})
+
+	return res, nil
+}
+
+// recordConn saves frames read from a websocket connection
+type recordConn struct {
+	wsConn
+	url      string
+	conn     int
+	recorder *recorder
+}
+
+// ReadMessage reads and records a single frame
+func (c *recordConn) ReadMessage() (int, []byte, error) {
+	messageType, message, err := c.wsConn.ReadMessage()
+	if err != nil {
+		return messageType, message, err
+	}
+
+	c.recorder.write(trafficEntry{
+		Kind:   "frame",
+		Offset: time.Since(c.recorder.start),
+		URL:    c.url,
+		Conn:   c.conn,
+		Body:   message,
+	})
+
+	return messageType, message, nil
+}
+
+// Replay serves API responses and websocket frames previously recorded in dir
+// instead of making network requests
+func Replay(dir string) error {
+	file, err := os.Open(filepath.Join(dir, trafficFile))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	t := &replayTransport{
+		start:     time.Now(),
+		responses: make(map[string][]trafficEntry),
+	}
+
+	dials := make(map[string][]trafficEntry)
+	frames := make(map[int][]trafficEntry)
+
+	decoder := json.NewDecoder(file)
+	for decoder.More() {
+		entry := trafficEntry{}
+		if err := decoder.Decode(&entry); err != nil {
+			return fmt.Errorf("failed to read recorded traffic: %v", err)
+		}
+
+		switch entry.Kind {
+		case "http":
+			key := entry.Method + " " + entry.URL
+			t.responses[key] = append(t.responses[key], entry)
+		case "dial":
+			dials[entry.URL] = append(dials[entry.URL], entry)
+		case "frame":
+			frames[entry.Conn] = append(frames[entry.Conn], entry)
+		}
+	}
+
+	var dialMutex sync.Mutex
+
+	trafficMutex.Lock()
+	defer trafficMutex.Unlock()
+
+	transport = t
+
+	dialWebsocket = func(ctx context.Context, url string) (wsConn, error) {
+		dialMutex.Lock()
+		defer dialMutex.Unlock()
+
+		if len(dials[url]) == 0 {
+			return nil, fmt.Errorf("no recorded websocket connection for %s", url)
+		}
+
+		dialEntry := dials[url][0]
+		dials[url] = dials[url][1:]
+
+		return &replayConn{
+			dialOffset: dialEntry.Offset,
+			dialTime:   time.Now(),
+			frames:     frames[dialEntry.Conn],
+			closed:     make(chan struct{}),
+		}, nil
+	}
+
+	return nil
+}
+
+// replayTransport serves recorded responses in the order they were received
+type replayTransport struct {
+	mutex     sync.Mutex
+	start     time.Time
+	responses map[string][]trafficEntry
+}
+
+// RoundTrip implements http.RoundTripper. A response is served no sooner
+// than it was received after recording started, and never faster than it
+// originally took. Once all responses recorded for a request are served, the
+// last one is repeated.
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.String()
+
+	t.mutex.Lock()
+	entries := t.responses[key]
+	if len(entries) == 0 {
+		t.mutex.Unlock()
+		return nil, fmt.Errorf("no recorded response for %s", key)
+	}
+	entry := entries[0]
+	if len(entries) > 1 {
+		t.responses[key] = entries[1:]
+	}
+	t.mutex.Unlock()
+
+	// Respond with the original timing
+	wait := time.Until(t.start.Add(entry.Offset + entry.Latency))
+	if wait < entry.Latency {
+		wait = entry.Latency
+	}
+
+	select {
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	case <-time.After(wait):
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+		StatusCode:    entry.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        entry.Header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}, nil
+}
+
+// replayConn serves recorded websocket frames with their original timing
+type replayConn struct {
+	dialOffset time.Duration
+	dialTime   time.Time
+	frames     []trafficEntry
+	closeOnce  sync.Once
+	closed     chan struct{}
+}
+
+// ReadMessage returns the next recorded frame once it is due. After the last
+// frame it blocks until the connection is closed.
+func (c *replayConn) ReadMessage() (int, []byte, error) {
+	if len(c.frames) == 0 {
+		<-c.closed
+		return 0, nil, fmt.Errorf("websocket closed")
+	}
+
+	frame := c.frames[0]
+	c.frames = c.frames[1:]
+
+	due := c.dialTime.Add(frame.Offset - c.dialOffset)
+	select {
+	case <-c.closed:
+		return 0, nil, fmt.Errorf("websocket closed")
+	case <-time.After(time.Until(due)):
+	}
+
+	return websocket.TextMessage, frame.Body, nil
+}
+
+// Close closes the connection
+func (c *replayConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// restoreTraffic restores the transport and dialer once a test ends
+func restoreTraffic(t *testing.T) {
+	trafficMutex.RLock()
+	savedTransport, savedDial := transport, dialWebsocket
+	trafficMutex.RUnlock()
+
+	t.Cleanup(func() {
+		StopRecording()
+
+		trafficMutex.Lock()
+		transport, dialWebsocket = savedTransport, savedDial
+		trafficMutex.Unlock()
+	})
+}
+
+// get requests url through the shared client, returning its body
+func get(t *testing.T, url string) (string, error) {
+	t.Helper()
+
+	res, err := HTTPClient().Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body), nil
+}
+
+func TestRecordReplay(t *testing.T) {
+	restoreTraffic(t)
+
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		switch r.URL.Path {
+		case "/coins":
+			fmt.Fprintf(w, `{"hit":%d}`, hits)
+		case "/limited":
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	dir := t.TempDir()
+	if err := Record(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Record two responses to the same request and a rate limited one
+	for _, want := range []string{`{"hit":1}`, `{"hit":2}`} {
+		body, err := get(t, server.URL+"/coins")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != want {
+			t.Fatalf("recording: got %s, want %s", body, want)
+		}
+	}
+	if _, err := get(t, server.URL+"/limited"); err == nil {
+		t.Fatal("recording: expected rate limit error")
+	}
+
+	if err := StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	// The 429 paused requests for an hour, which must not hold up replay
+	t.Cleanup(func() {
+		budget.SetRate(DefaultClientConfig().RequestsPerMinute)
+		budget.mutex.Lock()
+		budget.pausedUntil = time.Time{}
+		budget.mutex.Unlock()
+	})
+
+	if err := Replay(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Responses are served in order, the last is repeated
+	for _, want := range []string{`{"hit":1}`, `{"hit":2}`, `{"hit":2}`} {
+		body, err := get(t, server.URL+"/coins")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != want {
+			t.Errorf("replay: got %s, want %s", body, want)
+		}
+	}
+
+	_, err := get(t, server.URL+"/limited")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("replay: got error %v, want rate limit error", err)
+	}
+
+	if _, err := get(t, server.URL+"/unknown"); err == nil {
+		t.Error("replay: expected error for request which was not recorded")
+	}
+
+	if hits != 3 {
+		t.Errorf("server received %d requests, want 3", hits)
+	}
+}
+
+func TestReplayTiming(t *testing.T) {
+	restoreTraffic(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := Record(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Request once recording has run for a while
+	const offset = 100 * time.Millisecond
+	time.Sleep(offset)
+	if _, err := get(t, server.URL+"/coins"); err != nil {
+		t.Fatal(err)
+	}
+	if err := StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replay(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// The response is not served sooner than it was received
+	start := time.Now()
+	if _, err := get(t, server.URL+"/coins"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < offset {
+		t.Errorf("replayed response served after %v, want at least %v", elapsed, offset)
+	}
+}
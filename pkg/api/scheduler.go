@@ -0,0 +1,180 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// minBackoff is the delay before retrying a job after its first failure
+	minBackoff = 2 * time.Second
+	// maxBackoff limits the delay between retries of a failing job
+	maxBackoff = 2 * time.Minute
+	// jitterFraction is the fraction of a delay that is randomised
+	jitterFraction = 0.1
+)
+
+// budget is the request budget shared by all pollers
+var budget = newRequestBudget(DefaultClientConfig().RequestsPerMinute)
+
+// requestBudget is a token bucket limiting the rate of API requests
+type requestBudget struct {
+	mutex       sync.Mutex
+	rate        float64 // tokens added per second, unlimited if 0
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// newRequestBudget returns a requestBudget allowing requestsPerMinute requests
+func newRequestBudget(requestsPerMinute int) *requestBudget {
+	b := &requestBudget{}
+	b.SetRate(requestsPerMinute)
+	return b
+}
+
+// SetRate sets the number of requests allowed per minute, 0 removes the limit
+func (b *requestBudget) SetRate(requestsPerMinute int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.rate = float64(requestsPerMinute) / 60
+	b.burst = math.Max(1, math.Ceil(float64(requestsPerMinute)/6))
+	b.tokens = b.burst
+	b.last = time.Now()
+}
+
+// Pause stops requests from being made for duration d
+func (b *requestBudget) Pause(d time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if until := time.Now().Add(d); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	b.tokens = 0
+}
+
+// Wait blocks until a request can be made or the context is cancelled
+func (b *requestBudget) Wait(ctx context.Context) error {
+	for {
+		b.mutex.Lock()
+		now := time.Now()
+
+		var wait time.Duration
+		switch {
+		case now.Before(b.pausedUntil):
+			wait = b.pausedUntil.Sub(now)
+
+		case b.rate <= 0:
+			b.mutex.Unlock()
+			return nil
+
+		default:
+			b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+			b.last = now
+			if b.tokens >= 1 {
+				b.tokens--
+				b.mutex.Unlock()
+				return nil
+			}
+			wait = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		}
+		b.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// jitter randomises d by up to jitterFraction in either direction
+func jitter(d time.Duration) time.Duration {
+	delta := (rand.Float64()*2 - 1) * jitterFraction * float64(d)
+	return d + time.Duration(delta)
+}
+
+// backoff returns the delay before retrying a job which failed with err for
+// the given number of consecutive times
+func backoff(failures int, err error) time.Duration {
+	d := maxBackoff
+	if failures < 32 {
+		d = minBackoff * time.Duration(1<<uint(failures-1))
+		if d > maxBackoff || d <= 0 {
+			d = maxBackoff
+		}
+	}
+	d = jitter(d)
+
+	// Honour delays asked for by the API
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > d {
+		d = apiErr.RetryAfter
+	}
+
+	return d
+}
+
+// poll runs job every interval until the context is cancelled. A run only
+// starts after the previous one has finished, so slow requests never pile up.
+// Requests made by a job are abandoned once the context is cancelled, and
+// poll returns only once the job running has. Failed runs are passed to
+// onError and retried with exponential backoff. Errors which are not
+// retryable are retried at the slowest rate, so a dashboard left running
+// recovers once the cause is fixed. Runs wanting data the current provider
+// does not serve have nothing to serve, and run again at interval without
+// reporting an error.
+func poll(ctx context.Context, interval time.Duration, job func() error, onError func(error)) error {
+	failures := 0
+
+	for {
+		err := job()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Schedule next run
+		delay := jitter(interval)
+		if err != nil && !errors.Is(err, ErrUnsupported) {
+			onError(err)
+
+			failures++
+			if IsRetryable(err) {
+				delay = backoff(failures, err)
+			} else {
+				delay = jitter(maxBackoff)
+			}
+		} else {
+			failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// within reports whether d is within jitterFraction of want
+func within(d, want time.Duration) bool {
+	margin := time.Duration(jitterFraction * float64(want))
+	return d >= want-margin && d <= want+margin
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		err      error
+		want     time.Duration
+	}{
+		{1, errors.New("network error"), minBackoff},
+		{2, errors.New("network error"), 2 * minBackoff},
+		{3, errors.New("network error"), 4 * minBackoff},
+		{7, errors.New("network error"), maxBackoff},
+		{40, errors.New("network error"), maxBackoff},
+		{1, &APIError{StatusCode: http.StatusTooManyRequests}, minBackoff},
+
+		// Delays asked for by the API are honoured, even beyond maxBackoff
+		{1, &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute}, time.Minute},
+		{1, fmt.Errorf("markets: %w", &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}), time.Hour},
+	}
+
+	for _, tt := range tests {
+		got := backoff(tt.failures, tt.err)
+		if tt.want > maxBackoff {
+			if got != tt.want {
+				t.Errorf("backoff(%d, %v) = %v, want %v", tt.failures, tt.err, got, tt.want)
+			}
+			continue
+		}
+		if !within(got, tt.want) {
+			t.Errorf("backoff(%d, %v) = %v, want about %v", tt.failures, tt.err, got, tt.want)
+		}
+	}
+}
+
+// waits reports how many Waits on b succeed at once, up to max
+func waits(b *requestBudget, max int) int {
+	for i := 0; i < max; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		err := b.Wait(ctx)
+		cancel()
+		if err != nil {
+			return i
+		}
+	}
+	return max
+}
+
+func TestRequestBudget(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestsPerMinute int
+		pause             time.Duration
+		want              int
+	}{
+		{"unlimited", 0, 0, 100},
+		{"burst of a tenth of a minute", 60, 0, 10},
+		{"burst of at least one", 3, 0, 1},
+		{"paused", 60, time.Hour, 0},
+		{"paused while unlimited", 0, time.Hour, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newRequestBudget(tt.requestsPerMinute)
+			if tt.pause > 0 {
+				b.Pause(tt.pause)
+			}
+
+			if got := waits(b, 100); got != tt.want {
+				t.Errorf("%d requests made at once, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollKeepsPollingOnErrorsWhichAreNotRetryable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	rejected := &APIError{StatusCode: http.StatusUnauthorized}
+	runs, reported := 0, 0
+	err := poll(ctx, time.Millisecond, func() error {
+		runs++
+		return rejected
+	}, func(error) {
+		reported++
+	})
+
+	// The job waits for the slowest rate to be retried, until cancelled
+	if err != context.DeadlineExceeded {
+		t.Errorf("poll returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if runs != 1 || reported != 1 {
+		t.Errorf("job ran %d times and reported %d errors, want 1 and 1", runs, reported)
+	}
+}
+
+func TestPollServesNothingUnsupported(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	runs, reported := 0, 0
+	err := poll(ctx, 10*time.Millisecond, func() error {
+		runs++
+		return unsupported("categories")
+	}, func(error) {
+		reported++
+	})
+
+	// The job runs at its interval, without backing off or reporting errors
+	if err != context.DeadlineExceeded {
+		t.Errorf("poll returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if runs < 2 || reported != 0 {
+		t.Errorf("job ran %d times and reported %d errors, want at least 2 and 0", runs, reported)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// searchMaxAge is how long results of a search are reused before searching
+// again
+const searchMaxAge = 10 * time.Minute
+
+// searchEntry holds results of a search and when they were fetched
+type searchEntry struct {
+	results []SearchResult
+	fetched time.Time
+}
+
+var (
+	searchMutex sync.Mutex
+
+	// searchCache maps normalised queries to their results
+	searchCache = make(map[string]searchEntry)
+)
+
+// SearchCoins returns coins whose name or symbol match query, best matches
+// first. Results are cached for searchMaxAge, so queries typed again, such
+// as after deleting a character, cost no requests.
+func SearchCoins(ctx context.Context, query string) ([]SearchResult, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return []SearchResult{}, nil
+	}
+
+	searchMutex.Lock()
+	entry, ok := searchCache[query]
+	searchMutex.Unlock()
+	if ok && time.Since(entry.fetched) < searchMaxAge {
+		return entry.results, nil
+	}
+
+	provider, ok := CurrentProvider().(SearchProvider)
+	if !ok {
+		return nil, unsupported("search results")
+	}
+
+	results, err := provider.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	searchMutex.Lock()
+	defer searchMutex.Unlock()
+
+	searchCache[query] = searchEntry{results: results, fetched: time.Now()}
+	return results, nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// GetTrending serves market data of coins trending in searches on
+// dataChannel, priced in USD so it can be shown in any currency selected.
+// Nothing is served if the current provider does not serve trending coins.
+func GetTrending(ctx context.Context, dataChannel chan TrendingData, sendData *bool) error {
+	return poll(ctx, time.Duration(5)*time.Minute, func() error {
+		provider, ok := CurrentProvider().(TrendingProvider)
+		if !ok || !*sendData {
+			return nil
+		}
+
+		// Fetch Data
+		trending, err := provider.Trending(ctx)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, len(trending))
+		for i, coin := range trending {
+			ids[i] = coin.ID
+		}
+
+		coins := []CoinMarket{}
+		if len(ids) > 0 {
+			coins, err = getCoins(ctx, ids, "usd")
+			if err != nil {
+				return err
+			}
+		}
+
+		// Order coins as trending, coins without market data are left out
+		coinMap := make(map[string]CoinMarket, len(coins))
+		for _, coin := range coins {
+			coinMap[coin.ID] = coin
+		}
+
+		data := TrendingData{}
+		for _, id := range ids {
+			if coin, ok := coinMap[id]; ok {
+				data.Coins = append(data.Coins, coin)
+			}
+		}
+
+		// Send Data
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case dataChannel <- data:
+		}
+
+		return nil
+	}, func(err error) {
+		select {
+		case <-ctx.Done():
+		case dataChannel <- TrendingData{Err: err}:
+		}
+	})
+}
@@ -16,18 +16,28 @@ limitations under the License.
 
 package api
 
-import geckoTypes "github.com/superoo7/go-gecko/v3/types"
+import "time"
 
 // CoinData Holds data pertaining to a single coin.
 // This is used to serve per coin details.
 // It additionally holds a map of favourite coins.
+// Prices are in VsCurrency. If fetching data of the given Type failed, only
+// Err is set.
 type CoinData struct {
-	Type         string
-	PriceHistory []float64
-	MinPrice     float64
-	MaxPrice     float64
-	Details      CoinDetails
-	Favourites   map[string]float64
+	Type             string
+	VsCurrency       string
+	PriceHistory     []float64
+	MinPrice         float64
+	MaxPrice         float64
+	VolumeHistory    []float64 // 24 hour volumes over the price history
+	MarketCapHistory []float64
+	OHLC             []OHLC // Candles, for HISTORY when asked for
+	Details          CoinDetails
+	Favourites       map[string]float64 // prices by coin ID
+	Tickers          []Ticker
+	Holdings         []CoinMarket   // Market data of coins held, for PORTFOLIO
+	ActivitySince    ActivitySample // Activity trends are measured from, for DETAILS
+	Err              error
 }
 
 // CoinDetails holds information about a coin
@@ -37,7 +47,12 @@ type CoinDetails struct {
 	Rank           string
 	BlockTime      string
 	MarketCap      float64
+	Description    string // Plain text, HTML stripped
 	Website        string
+	Whitepaper     string
+	Repos          []string
+	Subreddit      string
+	Contracts      map[string]string // Contract addresses by platform
 	Explorers      [][]string
 	ATH            float64
 	ATHDate        string
@@ -50,16 +65,201 @@ type CoinDetails struct {
 	TotalSupply    float64
 	CurrentSupply  float64
 	LastUpdate     string
+	Activity       Activity
+}
+
+// Activity holds community and developer statistics of a coin, statistics
+// unknown to the provider are -1
+type Activity struct {
+	GitHubStars       int
+	Commits4Weeks     int
+	OpenIssues        int
+	RedditSubscribers int
+	TwitterFollowers  int
+}
+
+// ActivitySample holds the activity of a coin at a time
+type ActivitySample struct {
+	Time     time.Time `json:"time"`
+	Activity Activity  `json:"activity"`
 }
 
 // AssetData is used to hold details of multiple coins and the price history
-// of top ranked coins along with their names
+// of top ranked coins along with their names. Prices are in VsCurrency. If
+// fetching the data failed, only Err is set.
 type AssetData struct {
+	VsCurrency  string
+	Category    string          // ID of the category fetched, empty for all coins
+	InCategory  map[string]bool // IDs of coins in Category, nil for all coins
 	TopCoinData [][]float64
 	MaxPrices   []float64
 	MinPrices   []float64
 	TopCoins    []string
-	AllCoinData geckoTypes.CoinsMarket
+	AllCoinData []CoinMarket
+	Ranked      []CoinMarket // coins of AllCoinData among the top n, without favourites and coins held
+	Err         error
+}
+
+// CoinMarket holds market data of a single coin as served by a Provider.
+// PriceChangePercentage maps durations (1h, 24h, 7d, 14d, 30d, 200d, 1y) to
+// the change in price over that duration.
+type CoinMarket struct {
+	ID                    string
+	Symbol                string
+	Name                  string
+	MarketCapRank         int
+	CurrentPrice          float64
+	MarketCap             float64
+	TotalVolume           float64
+	High24                float64
+	Low24                 float64
+	CirculatingSupply     float64
+	TotalSupply           float64
+	MaxSupply             float64 // 0 if the supply is not capped or unknown
+	Sparkline7d           []float64
+	PriceChangePercentage map[string]float64
+}
+
+// MarketChart holds the price, market cap and volume history of a coin
+type MarketChart struct {
+	Prices       []float64
+	MarketCaps   []float64
+	TotalVolumes []float64
+	Times        []time.Time // Times of Prices, if served
+}
+
+// OHLC holds the open, high, low and close prices of a coin over a period
+// starting at Time
+type OHLC struct {
+	Time  time.Time
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+}
+
+// HistoryOptions selects the history served for a coin
+type HistoryOptions struct {
+	Interval string    // Key of the interval, such as "24hr"
+	Candles  bool      // Serve OHLC candles along with prices
+	From     time.Time // Start of a range served instead of the interval, if set
+	To       time.Time // End of the range, up to now if zero
+}
+
+// Ticker holds market data of a pair a coin trades in on an exchange. Last
+// is in the Target currency, LastUSD and VolumeUSD are converted to USD.
+type Ticker struct {
+	Exchange   string
+	Base       string
+	Target     string
+	Last       float64
+	LastUSD    float64
+	VolumeUSD  float64
+	Spread     float64 // Bid ask spread percentage
+	TrustScore string  // "green", "yellow", "red" or empty if unknown
+	IsAnomaly  bool
+	IsStale    bool
+}
+
+// GlobalMarket holds market wide data as served by a Provider. Totals map
+// lower case currency codes to values and Dominance maps lower case symbols
+// of the largest coins to their share of the total market cap, in percent.
+type GlobalMarket struct {
+	TotalMarketCap     map[string]float64
+	TotalVolume        map[string]float64
+	Dominance          map[string]float64
+	MarketCapChange24h float64
+	ActiveCoins        int
+	Markets            int
+}
+
+// DominanceSample holds the market cap dominance of BTC and ETH at a time
+type DominanceSample struct {
+	Time time.Time `json:"time"`
+	BTC  float64   `json:"btc"`
+	ETH  float64   `json:"eth"`
+}
+
+// GlobalData holds market wide data for the overview page, with totals in
+// VsCurrency and dominance recorded over time, oldest first. If fetching the
+// data failed, only Err is set.
+type GlobalData struct {
+	VsCurrency         string
+	TotalMarketCap     float64
+	TotalVolume        float64
+	MarketCapChange24h float64
+	ActiveCoins        int
+	Markets            int
+	DominanceHistory   []DominanceSample
+	Err                error
+}
+
+// CategoryMarket holds aggregate market data of a category of coins, such as
+// DeFi or Layer 1, as served by a Provider. Values are in USD.
+type CategoryMarket struct {
+	ID                 string  `json:"id"`
+	Name               string  `json:"name"`
+	MarketCap          float64 `json:"market_cap"`
+	MarketCapChange24h float64 `json:"market_cap_change_24h"`
+	Volume24h          float64 `json:"volume_24h"`
+}
+
+// CategoryData holds the categories coins can be browsed by, ordered by
+// market cap. If fetching them failed, only Err is set.
+type CategoryData struct {
+	Categories []CategoryMarket
+	Err        error
+}
+
+// SearchResult holds a coin matching a search as served by a Provider.
+// MarketCapRank is 0 for coins without a rank.
+type SearchResult struct {
+	ID            string
+	Symbol        string
+	Name          string
+	MarketCapRank int
+}
+
+// TrendingCoin holds a coin trending in searches as served by a Provider,
+// Score is its position among trending coins, from 0
+type TrendingCoin struct {
+	ID     string
+	Symbol string
+	Name   string
+	Score  int
+}
+
+// TrendingData holds market data of coins trending in searches, most
+// trending first, priced in USD. If fetching them failed, only Err is set.
+type TrendingData struct {
+	Coins []CoinMarket
+	Err   error
+}
+
+// CompareData holds the price history of coins compared over Interval, as
+// percent change from the start of the history of each coin, oldest first.
+// Histories are in the order the coins were given. If fetching them failed,
+// only Err is set.
+type CompareData struct {
+	Interval string
+	Changes  [][]float64
+	Err      error
+}
+
+// CurrencyRate holds the USD rate of a fiat or crypto currency
+type CurrencyRate struct {
+	ID             string  `json:"id"`
+	Symbol         string  `json:"symbol"`
+	CurrencySymbol string  `json:"currency_symbol"`
+	Type           string  `json:"type"`
+	RateUSD        float64 `json:"rate_usd"`
+}
+
+// CoinListItem identifies a coin listed by a Provider
+type CoinListItem struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
 }
 
 // CoinCapAsset is used to marshal asset data from coinCap APIs
@@ -84,11 +284,14 @@ type CoinCapData struct {
 	Timestamp uint           `json:"timestamp"`
 }
 
-// CoinID holds the ID of a coin as stored in CoinGecko and CoinCap
+// CoinID holds the ID of a coin as stored in CoinGecko and CoinCap, along
+// with details used to tell apart coins sharing a symbol. Rank and MarketCap
+// are 0 if unknown.
 type CoinID struct {
-	CoinGeckoID string
-	CoinCapID   string
+	CoinGeckoID string  `json:"coingecko_id"`
+	CoinCapID   string  `json:"coincap_id,omitempty"`
+	Symbol      string  `json:"symbol"`
+	Name        string  `json:"name"`
+	Rank        int     `json:"rank,omitempty"`
+	MarketCap   float64 `json:"market_cap,omitempty"`
 }
-
-// CoinIDMap maps a symbol to it's respective ID
-type CoinIDMap map[string]CoinID
@@ -0,0 +1,225 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// vsCurrenciesFile is the cache file of currencies prices can be fetched in
+const vsCurrenciesFile = "vs-currencies.json"
+
+// vsCurrenciesMaxAge is how long cached vs currencies are used before
+// refreshing them
+const vsCurrenciesMaxAge = 24 * time.Hour
+
+// subunit is a unit of a crypto currency worth a fixed fraction of it, such
+// as satoshis of bitcoin, which prices can be quoted in
+type subunit struct {
+	ID         string  // currency ID of the subunit
+	Symbol     string  // code of the subunit
+	ParentID   string  // currency ID of the currency it is part of
+	ParentCode string  // vs currency code of the currency it is part of
+	Size       float64 // value in the currency it is part of
+}
+
+// subunits lists subunits selectable as currencies, priced through the
+// currency they are part of
+var subunits = []subunit{
+	{ID: "satoshi", Symbol: "SATS", ParentID: "bitcoin", ParentCode: "btc", Size: 1e-8},
+	{ID: "gwei", Symbol: "GWEI", ParentID: "ethereum", ParentCode: "eth", Size: 1e-9},
+}
+
+var (
+	vsCurrenciesMutex sync.Mutex
+
+	// vsCurrencies holds the codes of currencies the current provider can
+	// price coins in, it is nil until loaded
+	vsCurrencies map[string]bool
+
+	// vsCurrenciesFetched is closed once currencies being fetched in the
+	// background are, it is nil while none are fetched
+	vsCurrenciesFetched chan struct{}
+)
+
+// loadVsCurrencies returns the currencies the current provider can price
+// coins in. They are read from the cache, even if it has expired, and
+// fetched in the background when expired, so callers such as the UI need
+// not wait on the network. When none are cached, such as on the first run,
+// it waits for them to be fetched, so the currency selected is not priced in
+// USD instead. Only USD is returned if they cannot be fetched.
+func loadVsCurrencies() map[string]bool {
+	vsCurrenciesMutex.Lock()
+
+	if vsCurrencies != nil {
+		defer vsCurrenciesMutex.Unlock()
+		return vsCurrencies
+	}
+
+	codes := []string{}
+	written, ok := readCache(vsCurrenciesFile, &codes)
+	if ok {
+		vsCurrencies = toSet(codes)
+	}
+
+	if (!ok || time.Since(written) > vsCurrenciesMaxAge) && vsCurrenciesFetched == nil {
+		vsCurrenciesFetched = make(chan struct{})
+		go refreshVsCurrencies(vsCurrenciesFetched)
+	}
+
+	if vsCurrencies != nil {
+		defer vsCurrenciesMutex.Unlock()
+		return vsCurrencies
+	}
+
+	// Wait for currencies to be fetched, outside the lock
+	fetched := vsCurrenciesFetched
+	vsCurrenciesMutex.Unlock()
+	<-fetched
+
+	vsCurrenciesMutex.Lock()
+	defer vsCurrenciesMutex.Unlock()
+
+	if vsCurrencies == nil {
+		return map[string]bool{"usd": true}
+	}
+	return vsCurrencies
+}
+
+// refreshVsCurrencies fetches the currencies the current provider can price
+// coins in and closes fetched, current ones are kept if they cannot be
+// fetched. Failing to fetch them when none are loaded is retried on the next
+// load.
+func refreshVsCurrencies(fetched chan struct{}) {
+	codes, err := fetchVsCurrencies(context.Background())
+
+	vsCurrenciesMutex.Lock()
+	defer vsCurrenciesMutex.Unlock()
+
+	vsCurrenciesFetched = nil
+	close(fetched)
+	if err == nil {
+		vsCurrencies = toSet(codes)
+	}
+}
+
+// fetchVsCurrencies fetches the currencies the current provider can price
+// coins in and caches them
+func fetchVsCurrencies(ctx context.Context) ([]string, error) {
+	provider, ok := CurrentProvider().(VsCurrenciesProvider)
+	if !ok {
+		return []string{"usd"}, nil
+	}
+
+	codes, err := provider.VsCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	writeCache(vsCurrenciesFile, codes)
+
+	return codes, nil
+}
+
+// toSet returns a set of the lower cased codes
+func toSet(codes []string) map[string]bool {
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[strings.ToLower(code)] = true
+	}
+
+	return set
+}
+
+// quoteIn returns the code prices should be fetched in for the currency with
+// the given symbol, such as "EUR", and the value of the currency in the
+// currency of the code. Subunits the provider lacks are fetched in the
+// currency they are part of. It is "usd" if the current provider cannot
+// price coins in the currency, prices must then be converted using currency
+// rates.
+func quoteIn(symbol string) (string, float64) {
+	supported := loadVsCurrencies()
+
+	code := strings.ToLower(symbol)
+	if supported[code] {
+		return code, 1
+	}
+
+	for _, unit := range subunits {
+		if strings.EqualFold(symbol, unit.Symbol) && supported[unit.ParentCode] {
+			return unit.ParentCode, unit.Size
+		}
+	}
+
+	return "usd", 1
+}
+
+// Currency holds the currency prices are fetched in. A page sets it when
+// the selected currency changes and the fetchers serving the page read it
+// on every fetch. Data fetched is tagged with the currency it is priced in,
+// as data fetched before a change may still be on its way.
+type Currency struct {
+	mutex sync.RWMutex
+	code  string
+	unit  float64 // value of the selected currency in the currency of code
+}
+
+// NewCurrency returns a Currency fetching prices in USD
+func NewCurrency() *Currency {
+	return &Currency{code: "usd", unit: 1}
+}
+
+// Set selects the currency with the given symbol and returns the code its
+// prices are fetched in
+func (c *Currency) Set(symbol string) string {
+	code, unit := quoteIn(symbol)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.code = code
+	c.unit = unit
+	return code
+}
+
+// Code returns the code of the currency prices are fetched in
+func (c *Currency) Code() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.code
+}
+
+// Divisor returns the number prices fetched in vsCurrency are divided by to
+// get them in the selected currency, given its USD rate. It reports false if
+// the prices were fetched in a currency selected earlier.
+func (c *Currency) Divisor(vsCurrency string, rateUSD float64) (float64, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	switch vsCurrency {
+	case "usd":
+		return rateUSD, true
+	case c.code:
+		return c.unit, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// vsCurrenciesProvider is a fakeProvider serving vs currencies slowly
+type vsCurrenciesProvider struct {
+	*fakeProvider
+	codes []string
+}
+
+func (p *vsCurrenciesProvider) VsCurrencies(ctx context.Context) ([]string, error) {
+	time.Sleep(10 * time.Millisecond)
+	return p.codes, nil
+}
+
+func TestQuoteIn(t *testing.T) {
+	useCacheDir(t)
+	useFakeProvider(t, &vsCurrenciesProvider{newFakeProvider(0), []string{"usd", "EUR", "btc"}})
+	resetVsCurrencies := func() {
+		vsCurrenciesMutex.Lock()
+		vsCurrencies = nil
+		vsCurrenciesMutex.Unlock()
+	}
+	resetVsCurrencies()
+	t.Cleanup(resetVsCurrencies)
+
+	// Nothing is cached, so the first currency selected waits for them
+	tests := []struct {
+		symbol string
+		code   string
+		unit   float64
+	}{
+		{"EUR", "eur", 1},
+		{"SATS", "btc", 1e-8},
+		{"GWEI", "usd", 1},
+		{"JPY", "usd", 1},
+	}
+
+	for _, tt := range tests {
+		if code, unit := quoteIn(tt.symbol); code != tt.code || unit != tt.unit {
+			t.Errorf("quoteIn(%s) = %s, %v, want %s, %v", tt.symbol, code, unit, tt.code, tt.unit)
+		}
+	}
+}
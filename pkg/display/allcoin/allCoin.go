@@ -26,18 +26,56 @@ import (
 
 	"github.com/Gituser143/cryptgo/pkg/api"
 	"github.com/Gituser143/cryptgo/pkg/display/coin"
+	"github.com/Gituser143/cryptgo/pkg/display/compare"
+	"github.com/Gituser143/cryptgo/pkg/display/movers"
+	"github.com/Gituser143/cryptgo/pkg/display/overview"
+	"github.com/Gituser143/cryptgo/pkg/display/search"
 	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/screener"
 	"github.com/Gituser143/cryptgo/pkg/utils"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
 	"golang.org/x/sync/errgroup"
 )
 
-func filterRows(allRows [][]string, filter string, m *sync.Mutex) [][]string {
+// maxCompared is the largest number of coins compared at once
+const maxCompared = 6
+
+// compareCoins returns the coins specified by symbols or IDs separated by
+// spaces or commas in input, taking the best ranked coin of a symbol.
+// Unknown and repeated coins are left out.
+func compareCoins(coinIDs *api.CoinIDMap, input string) []api.CoinID {
+	coins := []api.CoinID{}
+	seen := make(map[string]bool)
+
+	for _, token := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+		coinID := coinIDs.Get(strings.ToLower(token))
+		if candidates := coinIDs.Candidates(token); len(candidates) > 0 {
+			coinID = candidates[0]
+		} else if coinID.Symbol == "" {
+			continue
+		}
+
+		if !seen[coinID.CoinGeckoID] {
+			seen[coinID.CoinGeckoID] = true
+			coins = append(coins, coinID)
+		}
+	}
+
+	return coins
+}
+
+// filterRows returns rows whose symbol or name contain filter. If screened
+// is not nil, only rows of coins it holds are returned.
+func filterRows(allRows [][]string, filter string, screened map[string]bool, m *sync.Mutex) [][]string {
 	filteredRows := [][]string{}
 
 	m.Lock()
 	for _, row := range allRows {
+		if screened != nil && !screened[row[6]] {
+			continue
+		}
+
 		symbol, name := row[1], row[5]
 		if strings.Contains(symbol, filter) || strings.Contains(name, filter) {
 			filteredRows = append(filteredRows, row)
@@ -48,9 +86,11 @@ func filterRows(allRows [][]string, filter string, m *sync.Mutex) [][]string {
 	return filteredRows
 }
 
-// DisplayAllCoins displays the main page with top coin prices, favourites and
-// general coin asset data
-func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendData *bool) error {
+// DisplayAllCoins displays the main page with top coin prices, favourites,
+// trending coins and general coin asset data. The currency selected is set in
+// vsCurrency for the data to be fetched in, and the category of coins shown
+// in category. Coins are looked up in coinIDMap.
+func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, trendingChannel chan api.TrendingData, vsCurrency *api.Currency, category *api.Category, coinIDMap *api.CoinIDMap, priceHub *api.PriceHub, sendData *bool) error {
 
 	// Initialise UI
 	if err := ui.Init(); err != nil {
@@ -64,13 +104,17 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 	allRows := [][]string{}
 	var rowsMutex sync.Mutex
 
-	// Variables for CoinIDs
-	coinIDMap := api.NewCoinIDMap()
-	coinIDMap.Populate()
-
 	currencyWidget := uw.NewCurrencyPage()
 	currencyID := utils.GetCurrencyID()
 	currencyID, currency, currencyVal := currencyWidget.Get(currencyID)
+	vsCurrency.Set(currencyWidget.Code(currencyID))
+
+	// Variables for categories, categories are fetched each time the list is
+	// opened
+	categoryID, categoryName := utils.GetCategory()
+	category.Set(categoryID)
+	categoriesWidget := uw.NewCategoriesPage()
+	categoryChannel := make(chan api.CategoryData)
 
 	// Variables for percentage change
 	changePercent := "24h"
@@ -89,12 +133,66 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 		utils.SaveMetadata(favourites, currencyID, portfolioMap)
 	}()
 
+	// Subscribe to live prices, keeping the latest by coin ID
+	prices := priceHub.Subscribe()
+	livePrices := map[string]float64{}
+	defer func() {
+		priceHub.Unsubscribe(prices)
+		priceHub.SetAssets("coins", nil)
+	}()
+
 	// Initialise Help Menu
 	help := widgets.NewHelpMenu()
 	help.SelectHelpMenu("ALL")
 
-	// Initiliase Portfolio Table
+	// Initiliase Portfolio Table and picker for coins sharing a symbol
 	portfolioTable := uw.NewPortfolioPage()
+	coinPicker := uw.NewCoinPicker()
+
+	// Initialise error page and time since which coin data is stale,
+	// which is zero while data is up to date
+	errorPage := uw.NewErrorPage()
+	staleSince := time.Time{}
+
+	// Holdings last received, kept to show them again when amounts held
+	// change, and time since which they are stale
+	portfolioChannel := make(chan api.CoinData)
+	holdings := api.CoinData{}
+	holdingsStaleSince := time.Time{}
+
+	// updatePortfolio shows the holdings last received
+	updatePortfolio := func() {
+		if divisor, ok := vsCurrency.Divisor(holdings.VsCurrency, currencyVal); ok {
+			portfolioTable.UpdateRows(portfolioMap, holdings.Holdings, currency, divisor)
+		}
+		portfolioTable.Title = utils.StaleTitle(portfolioTable.Title, holdingsStaleSince)
+	}
+
+	// fetchHoldings asks for prices of coins held, which are received on
+	// portfolioChannel
+	fetchHoldings := func() {
+		ids := []string{}
+		for id := range portfolioMap {
+			ids = append(ids, id)
+		}
+		go api.GetHoldings(ctx, ids, vsCurrency, portfolioChannel)
+	}
+
+	// Trending coins last received, priced in USD, and time since which they
+	// are stale, which is zero while they are up to date
+	trendingCoins := []api.CoinMarket{}
+	trendingStaleSince := time.Time{}
+
+	// Variables for screener, screened holds IDs of coins passing the
+	// screen applied and is nil if none is. Coins last received are kept to
+	// screen them as soon as a screen is applied.
+	screens := utils.GetScreens()
+	screensWidget := uw.NewScreensPage()
+	var screen *screener.Query
+	screenName := ""
+	var screened map[string]bool
+	lastCoins := []api.CoinMarket{}
+	lastDivisor := 1.0
 
 	// Variables for sorting CoinTable
 	coinSortIdx := -1
@@ -122,6 +220,74 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 		*sendData = !(*sendData)
 	}
 
+	// runScreen sets the coins passing the screen applied
+	runScreen := func() {
+		if screen == nil {
+			screened = nil
+			return
+		}
+
+		screened = map[string]bool{}
+		for _, coin := range screen.Screen(lastCoins, lastDivisor) {
+			screened[coin.ID] = true
+		}
+	}
+
+	// coinTitle returns the title of the coin table, showing the filter and
+	// screen applied
+	coinTitle := func() string {
+		title := " Coins"
+		if filterStr != "" {
+			title += fmt.Sprintf(". Filter: '%s'", filterStr)
+		}
+		if categoryID != "" {
+			title += fmt.Sprintf(". Category: %s", categoryName)
+		}
+		if screen != nil {
+			title += fmt.Sprintf(". Screen: '%s'", screenName)
+		}
+		return utils.StaleTitle(title+" ", staleSince)
+	}
+
+	// markStale marks titles of widgets showing coin data if it is stale
+	markStale := func() {
+		page.CoinTable.Title = utils.StaleTitle(page.CoinTable.Title, staleSince)
+		page.FavouritesTable.Title = utils.StaleTitle(page.FavouritesTable.Title, staleSince)
+		for _, graph := range page.TopCoinGraphs {
+			graph.Title = utils.StaleTitle(graph.Title, staleSince)
+		}
+		page.TrendingTable.Title = utils.StaleTitle(" Trending ", trendingStaleSince)
+	}
+
+	// updateTrending lists trending coins in the selected currency,
+	// preferring live prices
+	updateTrending := func() {
+		rows := [][]string{}
+		for i, val := range trendingCoins {
+			price := val.CurrentPrice
+			if p, ok := livePrices[val.ID]; ok {
+				price = p
+			}
+
+			percentageChange := api.GetPercentageChangeForDuration(val, "24h")
+			change := fmt.Sprintf("%s %.2f", utils.UpArrow, percentageChange)
+			if percentageChange < 0 {
+				change = fmt.Sprintf("%s %.2f", utils.DownArrow, -percentageChange)
+			}
+
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", i+1),
+				strings.ToUpper(val.Symbol),
+				utils.FormatPrice(price / currencyVal),
+				change,
+				val.ID, // not displayed, identifies the coin
+			})
+		}
+
+		page.TrendingTable.Header[2] = fmt.Sprintf("Price (%s)", currency)
+		page.TrendingTable.Rows = rows
+	}
+
 	// UpdateUI to refresh UI
 	updateUI := func() {
 		// Get Terminal Dimensions
@@ -145,6 +311,18 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 		case uw.Change:
 			changePercentWidget.Resize(w, h)
 			ui.Render(changePercentWidget)
+		case uw.Pick:
+			coinPicker.Resize(w, h)
+			ui.Render(coinPicker)
+		case uw.Screens:
+			screensWidget.Resize(w, h)
+			ui.Render(screensWidget)
+		case uw.Categories:
+			categoriesWidget.Resize(w, h)
+			ui.Render(categoriesWidget)
+		case uw.Error:
+			errorPage.Resize(w, h)
+			ui.Render(page.Grid, errorPage)
 		default:
 			ui.Render(page.Grid)
 		}
@@ -156,6 +334,87 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 	// Create Channel to get keyboard events
 	uiEvents := ui.PollEvents()
 
+	// selectedCoin returns the ID and symbol of the coin under the cursor in
+	// the coin, favourites or trending table
+	selectedCoin := func() (string, string) {
+		table, idCol, symbolCol := page.CoinTable, 6, 1
+		switch selectedTable {
+		case page.FavouritesTable:
+			table, idCol, symbolCol = page.FavouritesTable, 2, 0
+		case page.TrendingTable:
+			table, idCol, symbolCol = page.TrendingTable, 4, 1
+		}
+
+		if table.SelectedRow < len(table.Rows) {
+			row := table.Rows[table.SelectedRow]
+			return row[idCol], row[symbolCol]
+		}
+
+		return "", ""
+	}
+
+	// showCoin serves the coin page of the coin specified by id until it is
+	// closed, pausing data sent to this page meanwhile
+	showCoin := func(coinGeckoID string) error {
+		// pause UI and data send, unpausing once closed
+		pause()
+		defer pause()
+
+		utils.SaveMetadata(favourites, currencyID, portfolioMap)
+
+		// Serve coin page until it is closed
+		err := coin.Show(ctx, coinGeckoID, currencyWidget.Code(currencyID), coinIDMap, favourites, priceHub, uiEvents)
+		if err != nil {
+			return err
+		}
+
+		currencyID = utils.GetCurrencyID()
+		currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+		vsCurrency.Set(currencyWidget.Code(currencyID))
+
+		return nil
+	}
+
+	// editHolding asks for the amount held of the coin specified by id and
+	// updates the portfolio
+	editHolding := func(id, symbol string) {
+		if id == "" {
+			return
+		}
+
+		inputStr := widgets.DrawEdit(uiEvents, symbol)
+		amt, err := strconv.ParseFloat(inputStr, 64)
+		if err == nil {
+			if amt > 0 {
+				portfolioMap[id] = amt
+			} else {
+				delete(portfolioMap, id)
+			}
+
+			// Save so coins outside the top coins are fetched
+			utils.SaveMetadata(favourites, currencyID, portfolioMap)
+		}
+	}
+
+	// applyScreen applies the screener query, named name if it is saved,
+	// and returns whether it could be parsed
+	applyScreen := func(name, query string) bool {
+		q, err := screener.Parse(query)
+		if err != nil {
+			screensWidget.SetError(err)
+			return false
+		}
+
+		screen, screenName = q, name
+		if screenName == "" {
+			screenName = query
+		}
+		runScreen()
+		page.CoinTable.Title = coinTitle()
+		page.CoinTable.ScrollTop()
+		return true
+	}
+
 	// Create ticker to periodically refresh UI
 	t := time.NewTicker(time.Duration(1) * time.Second)
 	tick := t.C
@@ -198,6 +457,13 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					selectedTable.ShowCursor = true
 				}
 
+			case "T":
+				if utilitySelected == uw.None {
+					selectedTable.ShowCursor = false
+					selectedTable = page.TrendingTable
+					selectedTable.ShowCursor = true
+				}
+
 			case "c":
 				if utilitySelected == uw.None {
 					selectedTable.ShowCursor = false
@@ -229,15 +495,209 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					selectedTable.ShowCursor = false
 					selectedTable = portfolioTable.Table
 					selectedTable.ShowCursor = true
-					portfolioTable.UpdateRows(portfolioMap, currency, currencyVal)
+					updatePortfolio()
+					fetchHoldings()
 					utilitySelected = uw.Portfolio
 				}
 
+			case "E":
+				if utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+
+			case "L":
+				if utilitySelected == uw.None {
+					// Search any coin, not only those shown
+					result, action, err := search.DisplaySearch(ctx, uiEvents)
+					if err != nil {
+						return err
+					}
+
+					switch action {
+					case search.Open:
+						if err := showCoin(result.ID); err != nil {
+							return err
+						}
+
+					case search.Favourite:
+						favourites[result.ID] = true
+
+						// Save so coins outside the top coins are fetched
+						utils.SaveMetadata(favourites, currencyID, portfolioMap)
+
+					case search.Hold:
+						editHolding(result.ID, strings.ToUpper(result.Symbol))
+					}
+				}
+
+			case "t":
+				if utilitySelected == uw.None {
+					selectedTable.ShowCursor = false
+					selectedTable = categoriesWidget.Table
+					selectedTable.ShowCursor = true
+					utilitySelected = uw.Categories
+
+					// Rows are listed once categories are received
+					go api.GetCategories(ctx, categoryChannel)
+				}
+
+			case "x":
+				if utilitySelected == uw.None {
+					selectedTable.ShowCursor = false
+					selectedTable = screensWidget.Table
+					selectedTable.ShowCursor = true
+					screensWidget.UpdateRows(screens)
+					utilitySelected = uw.Screens
+				}
+
+			case "n":
+				if utilitySelected == uw.Screens {
+					// Apply a new query, saving it if it is named
+					query := widgets.DrawPrompt(uiEvents, "Screener query, such as mcap > 1B and change7d < -10", "")
+					if strings.TrimSpace(query) == "" {
+						break
+					}
+					if _, err := screener.Parse(query); err != nil {
+						screensWidget.SetError(err)
+						break
+					}
+
+					name := strings.TrimSpace(widgets.DrawPrompt(uiEvents, "Name to save the screen as, empty to not save it", ""))
+					if name != "" {
+						screens[name] = query
+						utils.SaveScreens(screens)
+					}
+
+					if applyScreen(name, query) {
+						selectedTable = page.CoinTable
+						selectedTable.ShowCursor = true
+						utilitySelected = uw.None
+					}
+				}
+
+			case "d":
+				if utilitySelected == uw.Screens {
+					// Delete the selected screen
+					if name, _, ok := screensWidget.Selected(); ok {
+						delete(screens, name)
+						utils.SaveScreens(screens)
+						screensWidget.UpdateRows(screens)
+					}
+				}
+
+			case "O":
+				if utilitySelected == uw.None {
+					// Create new errorgroup for overview page, which reads
+					// coin data from this page's channel while shown
+					eg, overviewCtx := errgroup.WithContext(ctx)
+					globalChannel := make(chan api.GlobalData)
+
+					utils.SaveMetadata(favourites, currencyID, portfolioMap)
+
+					// Clear UI
+					ui.Clear()
+
+					// Serve market wide data
+					eg.Go(func() error {
+						return api.GetGlobalData(overviewCtx, vsCurrency, globalChannel)
+					})
+
+					// Serve Visuals for overview
+					eg.Go(func() error {
+						return overview.DisplayOverview(
+							overviewCtx,
+							vsCurrency,
+							globalChannel,
+							dataChannel,
+							uiEvents,
+						)
+					})
+
+					if err := eg.Wait(); err != nil {
+						if err.Error() != "UI Closed" {
+							return err
+						}
+					}
+
+					currencyID = utils.GetCurrencyID()
+					currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+					vsCurrency.Set(currencyWidget.Code(currencyID))
+				}
+
+			case "V":
+				if utilitySelected == uw.None {
+					// Compare coins given by symbol, starting with the
+					// selected coin
+					text := ""
+					if _, symbol := selectedCoin(); symbol != "" {
+						text = symbol + " "
+					}
+					title := fmt.Sprintf("Coins to compare, 2 to %d symbols, such as BTC ETH SOL", maxCompared)
+					coins := compareCoins(coinIDMap, widgets.DrawPrompt(uiEvents, title, text))
+
+					if len(coins) >= 2 && len(coins) <= maxCompared {
+						// pause data send while shown, unpausing once closed
+						pause()
+						utils.SaveMetadata(favourites, currencyID, portfolioMap)
+
+						ids := make([]string, len(coins))
+						for i, coinID := range coins {
+							ids[i] = coinID.CoinGeckoID
+						}
+
+						// Create new errorgroup for compare page
+						eg, compareCtx := errgroup.WithContext(ctx)
+						intervalChannel := make(chan string)
+						compareChannel := make(chan api.CompareData)
+
+						// Clear UI
+						ui.Clear()
+
+						// Serve histories of coins compared
+						eg.Go(func() error {
+							return api.GetCompareHistory(compareCtx, ids, intervalChannel, compareChannel)
+						})
+
+						// Serve Visuals for compare page
+						eg.Go(func() error {
+							return compare.DisplayCompare(compareCtx, coins, intervalChannel, compareChannel, uiEvents)
+						})
+
+						err := eg.Wait()
+						pause()
+						if err != nil && err.Error() != "UI Closed" {
+							return err
+						}
+					}
+				}
+
+			case "M":
+				if utilitySelected == uw.None {
+					utils.SaveMetadata(favourites, currencyID, portfolioMap)
+
+					// Serve movers page, which reads coin data from this
+					// page's channel while shown
+					ui.Clear()
+					err := movers.DisplayMovers(ctx, dataChannel, vsCurrency, coinIDMap, priceHub, sendData, uiEvents)
+					if err != nil && err.Error() != "UI Closed" {
+						return err
+					}
+
+					// Coin pages opened from it may have changed metadata
+					favourites = utils.GetFavourites()
+					portfolioMap = utils.GetPortfolio()
+					currencyID = utils.GetCurrencyID()
+					currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+					vsCurrency.Set(currencyWidget.Code(currencyID))
+				}
+
 			// Handle Navigations
 			case "<Escape>":
 				if utilitySelected == uw.None {
 					filterStr = ""
-					page.CoinTable.Title = " Coins "
+					screen = nil
+					runScreen()
+					page.CoinTable.Title = coinTitle()
 				}
 				utilitySelected = uw.None
 				selectedTable = page.CoinTable
@@ -276,68 +736,57 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 			// Handle Actions
 			case "e":
 				switch utilitySelected {
-				case uw.Portfolio:
-					id := ""
-					symbol := ""
-
-					// Get ID and symbol
-					if portfolioTable.SelectedRow < len(portfolioTable.Rows) {
-						row := portfolioTable.Rows[portfolioTable.SelectedRow]
-						symbol = row[1]
+				case uw.Screens:
+					// Edit the query of the selected screen
+					name, query, ok := screensWidget.Selected()
+					if !ok {
+						break
 					}
 
-					coinIDs := coinIDMap[symbol]
-
-					id = coinIDs.CoinGeckoID
-
-					if id != "" {
-						inputStr := widgets.DrawEdit(uiEvents, symbol)
-						amt, err := strconv.ParseFloat(inputStr, 64)
-
-						if err == nil {
-							if amt > 0 {
-								portfolioMap[id] = amt
-							} else {
-								delete(portfolioMap, id)
-							}
-						}
+					query = widgets.DrawPrompt(uiEvents, fmt.Sprintf("Query of %s", name), query)
+					if strings.TrimSpace(query) == "" {
+						break
+					}
+					if _, err := screener.Parse(query); err != nil {
+						screensWidget.SetError(err)
+						break
 					}
 
-					portfolioTable.UpdateRows(portfolioMap, currency, currencyVal)
-
-				case uw.None:
-					id := ""
-					symbol := ""
+					screens[name] = query
+					utils.SaveScreens(screens)
+					screensWidget.UpdateRows(screens)
 
+				case uw.Portfolio:
 					// Get ID and symbol
-					if selectedTable == page.CoinTable {
-						if page.CoinTable.SelectedRow < len(page.CoinTable.Rows) {
-							row := page.CoinTable.Rows[page.CoinTable.SelectedRow]
-							symbol = row[1]
-						}
-					} else {
-						if page.FavouritesTable.SelectedRow < len(page.FavouritesTable.Rows) {
-							row := page.FavouritesTable.Rows[page.FavouritesTable.SelectedRow]
-							symbol = row[0]
-						}
+					if portfolioTable.SelectedRow < len(portfolioTable.Rows) {
+						row := portfolioTable.Rows[portfolioTable.SelectedRow]
+						editHolding(row[5], row[1])
 					}
 
-					coinIDs := coinIDMap[symbol]
+					updatePortfolio()
 
-					id = coinIDs.CoinGeckoID
-
-					if id != "" {
-
-						inputStr := widgets.DrawEdit(uiEvents, symbol)
+				case uw.None:
+					editHolding(selectedCoin())
+				}
 
-						amt, err := strconv.ParseFloat(inputStr, 64)
-						if err == nil {
-							if amt > 0 {
-								portfolioMap[id] = amt
-							} else {
-								delete(portfolioMap, id)
-							}
-						}
+			case "a":
+				if utilitySelected == uw.Portfolio {
+					// Add coin by symbol, asking which coin is meant if many
+					// coins share the symbol
+					inputStr := widgets.DrawEdit(uiEvents, "")
+					candidates := coinIDMap.Candidates(strings.TrimSpace(inputStr))
+
+					switch {
+					case len(candidates) == 1:
+						editHolding(candidates[0].CoinGeckoID, candidates[0].Symbol)
+						fetchHoldings()
+
+					case len(candidates) > 1:
+						coinPicker.Set(candidates)
+						selectedTable.ShowCursor = false
+						selectedTable = coinPicker.Table
+						selectedTable.ShowCursor = true
+						utilitySelected = uw.Pick
 					}
 				}
 
@@ -346,7 +795,7 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 				case uw.None:
 					inputStr := widgets.DrawEdit(uiEvents, "")
 					filterStr = strings.ToUpper(strings.Trim(inputStr, " \t\n"))
-					page.CoinTable.Title = fmt.Sprintf(" Coins. Filter: '%s' ", filterStr)
+					page.CoinTable.Title = coinTitle()
 				}
 
 			case "<Enter>":
@@ -360,6 +809,7 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 						// Get currency and rate
 						currencyID = row[0]
 						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+						vsCurrency.Set(currencyWidget.Code(currencyID))
 
 						// Update currency fields
 						coinHeader[2] = fmt.Sprintf("Price (%s)", currency)
@@ -377,108 +827,52 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 					}
 					utilitySelected = uw.None
 
-				case uw.None:
-					// pause UI and data send
-					pause()
+				case uw.Categories:
+					// Show coins in the selected category, which are shown
+					// once fetched
+					if id, name, ok := categoriesWidget.Selected(); ok && id != categoryID {
+						categoryID, categoryName = id, name
+						category.Set(categoryID)
+						utils.SaveCategory(categoryID, categoryName)
+
+						rowsMutex.Lock()
+						allRows = [][]string{}
+						rowsMutex.Unlock()
+						page.CoinTable.Title = coinTitle()
+						page.CoinTable.ScrollTop()
+					}
+					selectedTable = page.CoinTable
+					selectedTable.ShowCursor = true
+					utilitySelected = uw.None
 
-					symbol := ""
+				case uw.Screens:
+					// Apply the selected screen
+					if name, query, ok := screensWidget.Selected(); ok && applyScreen(name, query) {
+						selectedTable = page.CoinTable
+						selectedTable.ShowCursor = true
+						utilitySelected = uw.None
+					}
 
-					// Get ID and symbol
-					if selectedTable == page.CoinTable {
-						if page.CoinTable.SelectedRow < len(page.CoinTable.Rows) {
-							row := page.CoinTable.Rows[page.CoinTable.SelectedRow]
-							symbol = row[1]
-						}
-					} else {
-						if page.FavouritesTable.SelectedRow < len(page.FavouritesTable.Rows) {
-							row := page.FavouritesTable.Rows[page.FavouritesTable.SelectedRow]
-							symbol = row[0]
-						}
+				case uw.Pick:
+					// Add picked coin to portfolio
+					if coin, ok := coinPicker.Selected(); ok {
+						editHolding(coin.CoinGeckoID, coin.Symbol)
 					}
-					coinIDs := coinIDMap[symbol]
 
-					coinCapID := coinIDs.CoinCapID
-					coinGeckoID := coinIDs.CoinGeckoID
+					selectedTable = portfolioTable.Table
+					selectedTable.ShowCursor = true
+					fetchHoldings()
+					utilitySelected = uw.Portfolio
 
+				case uw.None:
+					// Get IDs
+					coinGeckoID, _ := selectedCoin()
 					if coinGeckoID != "" {
-						// Create new errorgroup for coin page
-						eg, coinCtx := errgroup.WithContext(ctx)
-						coinDataChannel := make(chan api.CoinData)
-						coinPriceChannel := make(chan string)
-						intervalChannel := make(chan string)
-
-						// Clear UI
-						ui.Clear()
-
-						// Serve Coin Price History
-						eg.Go(func() error {
-							err := api.GetCoinHistory(
-								coinCtx,
-								coinGeckoID,
-								intervalChannel,
-								coinDataChannel,
-							)
-							return err
-						})
-
-						// Serve Coin Asset data
-						eg.Go(func() error {
-							err := api.GetCoinDetails(coinCtx, coinGeckoID, coinDataChannel)
+						if err := showCoin(coinGeckoID); err != nil {
 							return err
-						})
-
-						// Serve favourite coin prices
-						eg.Go(func() error {
-							err := api.GetFavouritePrices(coinCtx,
-								favourites,
-								coinDataChannel,
-							)
-							return err
-						})
-
-						// Serve Live price of coin
-						if coinCapID != "" {
-							eg.Go(func() error {
-								api.GetLivePrice(coinCtx, coinCapID, coinPriceChannel)
-								// Send NA to indicate price is not being updated
-								go func() {
-									coinPriceChannel <- "NA"
-								}()
-								return nil
-							})
 						}
-
-						utils.SaveMetadata(favourites, currencyID, portfolioMap)
-
-						// Serve Visuals for coin
-						eg.Go(func() error {
-							err := coin.DisplayCoin(
-								coinCtx,
-								coinGeckoID,
-								coinIDMap,
-								intervalChannel,
-								coinDataChannel,
-								coinPriceChannel,
-								uiEvents,
-							)
-							return err
-						})
-
-						if err := eg.Wait(); err != nil {
-							if err.Error() != "UI Closed" {
-								// Unpause
-								pause()
-								return err
-							}
-						}
-
-						currencyID = utils.GetCurrencyID()
-						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
-
 					}
 
-					// unpause data send and receive
-					pause()
 					updateUI()
 					utilitySelected = uw.None
 				}
@@ -491,49 +885,20 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 
 			case "s":
 				if utilitySelected == uw.None {
-					id := ""
-					symbol := ""
+					id, _ := selectedCoin()
+					if id != "" {
+						favourites[id] = true
 
-					// Get ID and symbol
-					if selectedTable == page.CoinTable {
-						if page.CoinTable.SelectedRow < len(page.CoinTable.Rows) {
-							row := page.CoinTable.Rows[page.CoinTable.SelectedRow]
-							symbol = row[1]
-						}
-					} else {
-						if page.FavouritesTable.SelectedRow < len(page.FavouritesTable.Rows) {
-							row := page.FavouritesTable.Rows[page.FavouritesTable.SelectedRow]
-							symbol = row[0]
-						}
+						// Save so coins outside the top coins are fetched
+						utils.SaveMetadata(favourites, currencyID, portfolioMap)
 					}
-					coinIDs := coinIDMap[symbol]
-					id = coinIDs.CoinGeckoID
-					favourites[id] = true
 				}
 
 			case "S":
 				if utilitySelected == uw.None {
-					id := ""
-					symbol := ""
-
-					// Get ID and symbol
-					if selectedTable == page.CoinTable {
-						if page.CoinTable.SelectedRow < len(page.CoinTable.Rows) {
-							row := page.CoinTable.Rows[page.CoinTable.SelectedRow]
-							symbol = row[1]
-						}
-					} else {
-						if page.FavouritesTable.SelectedRow < len(page.FavouritesTable.Rows) {
-							row := page.FavouritesTable.Rows[page.FavouritesTable.SelectedRow]
-							symbol = row[0]
-						}
-					}
-
-					coinIDs := coinIDMap[symbol]
-
-					id = coinIDs.CoinGeckoID
-
+					id, _ := selectedCoin()
 					delete(favourites, id)
+					utils.SaveMetadata(favourites, currencyID, portfolioMap)
 				}
 			}
 
@@ -592,9 +957,38 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 			}
 
 		case data := <-dataChannel:
+			if data.Err != nil {
+				if staleSince.IsZero() && utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+				staleSince = errorPage.Fail("Coin", data.Err, staleSince)
+				markStale()
+				break
+			}
+			staleSince = time.Time{}
+
+			// Skip data priced in a currency or fetched for a category
+			// selected earlier
+			divisor, ok := vsCurrency.Divisor(data.VsCurrency, currencyVal)
+			if !ok || data.Category != categoryID {
+				break
+			}
+
+			// Clear graphs of top coins a category lacks
+			for i := len(data.TopCoinData); i < len(page.TopCoinGraphs); i++ {
+				page.TopCoinGraphs[i].Title = " "
+				page.TopCoinGraphs[i].Data["Value"] = []float64{}
+				for label := range page.TopCoinGraphs[i].Labels {
+					page.TopCoinGraphs[i].Labels[label] = ""
+				}
+			}
 
 			// Update Top Coin data
 			for i, v := range data.TopCoinData {
+				if i >= len(page.TopCoinGraphs) || len(v) == 0 {
+					continue
+				}
+
 				// Set title to coin name
 				page.TopCoinGraphs[i].Title = fmt.Sprintf(" %s (7D) ", data.TopCoins[i])
 
@@ -602,14 +996,14 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 				page.TopCoinGraphs[i].Data["Value"] = v
 
 				// Set value, max & min values
-				maxValue := data.MaxPrices[i] / currencyVal
-				minValue := data.MinPrices[i] / currencyVal
+				maxValue := data.MaxPrices[i] / divisor
+				minValue := data.MinPrices[i] / divisor
 				// Current value is last point (cleaned) in graph + minimum value
-				value := (v[len(v)-1] + data.MinPrices[i]) / currencyVal
+				value := (v[len(v)-1] + data.MinPrices[i]) / divisor
 
-				page.TopCoinGraphs[i].Labels["Value"] = fmt.Sprintf("%.2f %s", value, currency)
-				page.TopCoinGraphs[i].Labels["Max"] = fmt.Sprintf("%.2f %s", maxValue, currency)
-				page.TopCoinGraphs[i].Labels["Min"] = fmt.Sprintf("%.2f %s", minValue, currency)
+				page.TopCoinGraphs[i].Labels["Value"] = fmt.Sprintf("%s %s", utils.FormatPrice(value), currency)
+				page.TopCoinGraphs[i].Labels["Max"] = fmt.Sprintf("%s %s", utils.FormatPrice(maxValue), currency)
+				page.TopCoinGraphs[i].Labels["Min"] = fmt.Sprintf("%s %s", utils.FormatPrice(minValue), currency)
 			}
 
 			favouritesData := [][]string{}
@@ -624,8 +1018,12 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 
 			// Iterate over coin assets
 			for _, val := range data.AllCoinData {
-				// Get coin price
-				price := fmt.Sprintf("%.2f", val.CurrentPrice/currencyVal)
+				// Get coin price, preferring the live price in USD
+				currentPrice := val.CurrentPrice / divisor
+				if p, ok := livePrices[val.ID]; ok {
+					currentPrice = p / currencyVal
+				}
+				price := utils.FormatPrice(currentPrice)
 
 				// Get change %
 				var change string
@@ -655,27 +1053,36 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 
 				rank := fmt.Sprintf("%d", val.MarketCapRank)
 
-				// Aggregate data
-				allRows = append(allRows, []string{
-					rank,
-					strings.ToUpper(val.Symbol),
-					price,
-					change,
-					supplyData,
-					strings.ToUpper(val.Name), // not displayed, used for filter purpose
-				})
+				// Aggregate data, favourites and coins held outside the
+				// category are only shown as favourites
+				if data.InCategory == nil || data.InCategory[val.ID] {
+					allRows = append(allRows, []string{
+						rank,
+						strings.ToUpper(val.Symbol),
+						price,
+						change,
+						supplyData,
+						strings.ToUpper(val.Name), // not displayed, used for filter purpose
+						val.ID,                    // not displayed, identifies the coin
+					})
+				}
 
 				// Aggregate favourite data
 				if _, ok := favourites[val.ID]; ok {
 					favouritesData = append(favouritesData, []string{
 						strings.ToUpper(val.Symbol),
 						price,
+						val.ID, // not displayed, identifies the coin
 					})
 				}
 			}
 			rowsMutex.Unlock()
 
-			page.CoinTable.Rows = filterRows(allRows, filterStr, &rowsMutex)
+			// Screen coins received
+			lastCoins, lastDivisor = data.AllCoinData, divisor
+			runScreen()
+
+			page.CoinTable.Rows = filterRows(allRows, filterStr, screened, &rowsMutex)
 			page.FavouritesTable.Rows = favouritesData
 
 			// Sort CoinTable data
@@ -700,9 +1107,94 @@ func DisplayAllCoins(ctx context.Context, dataChannel chan api.AssetData, sendDa
 				}
 			}
 
+			markStale()
+
+		case data := <-trendingChannel:
+			if data.Err != nil {
+				if trendingStaleSince.IsZero() && utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+				trendingStaleSince = errorPage.Fail("Trending", data.Err, trendingStaleSince)
+				markStale()
+				break
+			}
+			trendingStaleSince = time.Time{}
+
+			trendingCoins = data.Coins
+			updateTrending()
+			markStale()
+
+		case data := <-portfolioChannel:
+			if data.Err != nil {
+				holdingsStaleSince = errorPage.Fail("Portfolio", data.Err, holdingsStaleSince)
+				updatePortfolio()
+				break
+			}
+			holdingsStaleSince = time.Time{}
+
+			holdings = data
+			updatePortfolio()
+
+		case data := <-categoryChannel:
+			if data.Err != nil {
+				categoriesWidget.SetError(data.Err)
+				break
+			}
+			categoriesWidget.UpdateRows(data.Categories, currency, currencyVal)
+
+		case <-prices.C:
+			updated := prices.Prices()
+			for id, p := range updated {
+				livePrices[id] = p
+			}
+
+			// Update prices of coins and favourites
+			rowsMutex.Lock()
+			for _, row := range allRows {
+				if p, ok := updated[row[6]]; ok {
+					row[2] = utils.FormatPrice(p / currencyVal)
+				}
+			}
+			if coinSortIdx != -1 {
+				utils.SortData(allRows, coinSortIdx, coinSortAsc, "COINS")
+			}
+			rowsMutex.Unlock()
+
+			for _, row := range page.FavouritesTable.Rows {
+				if p, ok := updated[row[2]]; ok {
+					row[1] = utils.FormatPrice(p / currencyVal)
+				}
+			}
+
 		case <-tick: // Refresh UI
 			// Filter Data
-			page.CoinTable.Rows = filterRows(allRows, filterStr, &rowsMutex)
+			page.CoinTable.Rows = filterRows(allRows, filterStr, screened, &rowsMutex)
+			updateTrending()
+
+			// Stream live prices of coins in view and favourites, forgetting
+			// prices of coins no longer streamed as they go out of date
+			ids := []string{}
+			top := page.CoinTable.TopRow
+			for i := top; i < len(page.CoinTable.Rows) && i < top+page.CoinTable.Inner.Dy()-1; i++ {
+				ids = append(ids, page.CoinTable.Rows[i][6])
+			}
+			for _, row := range page.FavouritesTable.Rows {
+				ids = append(ids, row[2])
+			}
+			for _, coin := range trendingCoins {
+				ids = append(ids, coin.ID)
+			}
+			priceHub.SetAssets("coins", ids)
+
+			streamed := map[string]bool{}
+			for _, id := range ids {
+				streamed[id] = true
+			}
+			for id := range livePrices {
+				if !streamed[id] {
+					delete(livePrices, id)
+				}
+			}
 
 			if *sendData {
 				updateUI()
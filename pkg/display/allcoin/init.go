@@ -27,6 +27,7 @@ type allCoinPage struct {
 	CoinTable       *widgets.Table
 	TopCoinGraphs   []*widgets.LineGraph
 	FavouritesTable *widgets.Table
+	TrendingTable   *widgets.Table
 }
 
 // newallCoinPage creates, initialises and returns a pointer to an instance of allCoinPage
@@ -41,6 +42,7 @@ func newAllCoinPage() *allCoinPage {
 		CoinTable:       widgets.NewTable(),
 		TopCoinGraphs:   coinGraphs,
 		FavouritesTable: widgets.NewTable(),
+		TrendingTable:   widgets.NewTable(),
 	}
 
 	page.init()
@@ -68,6 +70,7 @@ func (page *allCoinPage) init() {
 	page.CoinTable.ShowCursor = true
 	page.CoinTable.CursorColor = ui.ColorCyan
 	page.CoinTable.ChangeCol[3] = true
+	page.CoinTable.UniqueCol = 6
 
 	// Initialise Favourites table
 	page.FavouritesTable.Title = " Favourites "
@@ -82,6 +85,25 @@ func (page *allCoinPage) init() {
 		}
 	}
 	page.FavouritesTable.CursorColor = ui.ColorCyan
+	page.FavouritesTable.UniqueCol = 2
+
+	// Initialise Trending table
+	page.TrendingTable.Title = " Trending "
+	page.TrendingTable.BorderStyle.Fg = ui.ColorCyan
+	page.TrendingTable.TitleStyle.Fg = ui.ColorClear
+	page.TrendingTable.Header = []string{"#", "Symbol", "Price", "Change %(24h)"}
+	page.TrendingTable.ColResizer = func() {
+		x := page.TrendingTable.Inner.Dx()
+		page.TrendingTable.ColWidths = []int{
+			x / 10,
+			2 * x / 10,
+			4 * x / 10,
+			3 * x / 10,
+		}
+	}
+	page.TrendingTable.CursorColor = ui.ColorCyan
+	page.TrendingTable.ChangeCol[3] = true
+	page.TrendingTable.UniqueCol = 4
 
 	// Initialise Top Coin Graphs
 	for i := 0; i < 3; i++ {
@@ -104,7 +126,10 @@ func (page *allCoinPage) init() {
 			ui.NewCol(0.34, page.TopCoinGraphs[2]),
 		),
 		ui.NewRow(0.67,
-			ui.NewCol(0.33, page.FavouritesTable),
+			ui.NewCol(0.33,
+				ui.NewRow(0.5, page.FavouritesTable),
+				ui.NewRow(0.5, page.TrendingTable),
+			),
 			ui.NewCol(0.67, page.CoinTable),
 		),
 	)
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Gituser143/cryptgo/pkg/api"
@@ -29,18 +30,30 @@ import (
 	ui "github.com/gizak/termui/v3"
 )
 
-// DisplayCoin displays the per coin values and details along with a favourites table. It uses the same uiEvents channel as the root page
+// DisplayCoin displays the per coin values and details along with a favourites table. It uses the same uiEvents channel as the root page.
+// Live prices of the coin and of favourites are streamed from priceHub.
+// The currency selected is set in vsCurrency for the data to be fetched in.
 func DisplayCoin(
 	ctx context.Context,
 	id string,
-	coinIDs api.CoinIDMap,
-	intervalChannel chan string,
+	coinIDs *api.CoinIDMap,
+	vsCurrency *api.Currency,
+	historyChannel chan api.HistoryOptions,
 	dataChannel chan api.CoinData,
-	priceChannel chan string,
+	priceHub *api.PriceHub,
 	uiEvents <-chan ui.Event) error {
 
 	defer ui.Clear()
 
+	// Subscribe to live prices, keeping the latest by coin ID
+	prices := priceHub.Subscribe()
+	priceHub.SetAssets("coin", []string{id})
+	livePrices := map[string]float64{}
+	defer func() {
+		priceHub.Unsubscribe(prices)
+		priceHub.SetAssets("coin", nil)
+	}()
+
 	// Init Coin page
 	page := newCoinPage()
 
@@ -49,10 +62,52 @@ func DisplayCoin(
 
 	currencyID := utils.GetCurrencyID()
 	currencyID, currency, currencyVal := currencyWidget.Get(currencyID)
+	vsCurrency.Set(currencyWidget.Code(currencyID))
 
-	// variables for graph interval
+	// variables for graph interval and view, candles are shown instead of
+	// the line graph when set. A range, if set, is shown instead of the
+	// interval.
 	changeInterval := "24 Hours"
 	changeIntervalWidget := uw.NewChangeIntervalPage()
+	candles := false
+	history := api.DefaultHistory()
+	rangeFrom, rangeTo := history.From, history.To
+	for name, interval := range uw.IntervalMap {
+		if interval == history.Interval {
+			changeInterval = name
+		}
+	}
+	if !rangeFrom.IsZero() {
+		changeInterval = utils.FormatDateRange(rangeFrom, rangeTo)
+	}
+
+	// sendHistoryOptions asks for history of the selected interval or range
+	// and view
+	sendHistoryOptions := func() {
+		historyChannel <- api.HistoryOptions{
+			Interval: uw.IntervalMap[changeInterval],
+			Candles:  candles,
+			From:     rangeFrom,
+			To:       rangeTo,
+		}
+	}
+
+	// readDate asks for a date until a valid one is entered, an empty date is
+	// returned as zero
+	readDate := func(title, text string) time.Time {
+		for {
+			input := widgets.DrawPrompt(uiEvents, title, text)
+			if strings.TrimSpace(input) == "" {
+				return time.Time{}
+			}
+
+			date, err := utils.ParseDate(input)
+			if err == nil {
+				return date
+			}
+			title, text = err.Error(), input
+		}
+	}
 
 	// Selection of default table
 	selectedTable := page.ExplorerTable
@@ -74,13 +129,134 @@ func DisplayCoin(
 		utils.SaveMetadata(favourites, currencyID, portfolioMap)
 	}()
 
-	// Initiliase Portfolio Table
+	// Initiliase Portfolio Table, holdings are kept to show them again when
+	// amounts held change
 	portfolioTable := uw.NewPortfolioPage()
+	holdings := api.CoinData{}
+
+	// updatePortfolio shows the holdings last received
+	updatePortfolio := func() {
+		if divisor, ok := vsCurrency.Divisor(holdings.VsCurrency, currencyVal); ok {
+			portfolioTable.UpdateRows(portfolioMap, holdings.Holdings, currency, divisor)
+		}
+	}
+
+	// fetchHoldings asks for prices of coins held, which are received on
+	// dataChannel
+	fetchHoldings := func() {
+		ids := []string{}
+		for id := range portfolioMap {
+			ids = append(ids, id)
+		}
+		go api.GetHoldings(ctx, ids, vsCurrency, dataChannel)
+	}
+
+	// Initialise Markets Table, tickers are kept to show them again when
+	// the currency changes
+	marketsTable := uw.NewMarketsPage()
+	tickers := []api.Ticker{}
+
+	// Initialise Activity Table
+	activityTable := uw.NewActivityPage()
+
+	// Initialise Info Page
+	infoPage := uw.NewInfoPage()
+
+	// openLink opens the link selected on the info page in a browser
+	openLink := func() {
+		link := infoPage.Selected()
+		if !strings.HasPrefix(link, "http") {
+			return
+		}
+		if err := utils.OpenURL(link); err != nil {
+			infoPage.SetStatus(err.Error())
+		} else {
+			infoPage.SetStatus("opened " + link)
+		}
+	}
 
 	// Initialise help menu
 	help := widgets.NewHelpMenu()
 	help.SelectHelpMenu("COIN")
 
+	// Initialise error page and times since which each type of data is
+	// stale, types missing from staleSince are up to date
+	errorPage := uw.NewErrorPage()
+	staleSince := map[string]time.Time{}
+	sources := map[string]string{
+		"FAVOURITES": "Favourites",
+		"HISTORY":    "Price history",
+		"DETAILS":    "Coin details",
+		"TICKERS":    "Markets",
+		"PORTFOLIO":  "Portfolio",
+	}
+
+	// markStale marks titles of widgets showing stale data
+	markStale := func() {
+		page.FavouritesTable.Title = utils.StaleTitle(page.FavouritesTable.Title, staleSince["FAVOURITES"])
+		page.ValueGraph.Title = utils.StaleTitle(page.ValueGraph.Title, staleSince["HISTORY"])
+		page.CandleChart.Title = utils.StaleTitle(page.CandleChart.Title, staleSince["HISTORY"])
+		page.VolumeChart.Title = utils.StaleTitle(page.VolumeChart.Title, staleSince["HISTORY"])
+		page.DetailsTable.Title = utils.StaleTitle(page.DetailsTable.Title, staleSince["DETAILS"])
+		page.ChangesTable.Title = utils.StaleTitle(page.ChangesTable.Title, staleSince["DETAILS"])
+		page.SupplyChart.Title = utils.StaleTitle(page.SupplyChart.Title, staleSince["DETAILS"])
+		marketsTable.Title = utils.StaleTitle(marketsTable.Title, staleSince["TICKERS"])
+		activityTable.Title = utils.StaleTitle(activityTable.Title, staleSince["DETAILS"])
+		infoPage.Title = utils.StaleTitle(infoPage.Title, staleSince["DETAILS"])
+		portfolioTable.Title = utils.StaleTitle(portfolioTable.Title, staleSince["PORTFOLIO"])
+	}
+
+	// Volume and market cap history in the selected currency, and cleaned
+	// price history to colour volumes by price moves
+	volumeHistory := []float64{}
+	marketCapHistory := []float64{}
+	priceHistory := []float64{}
+	showMarketCap := false
+
+	// updateVolumes fits volumes to the width of the volume chart, colouring
+	// a bar red if the price fell since the previous bar
+	updateVolumes := func() {
+		n := page.VolumeChart.Inner.Dx() / (page.VolumeChart.BarWidth + page.VolumeChart.BarGap)
+		volumes := utils.Resample(volumeHistory, n)
+		prices := utils.Resample(priceHistory, len(volumes))
+
+		colors := make([]ui.Color, len(volumes))
+		for i := range colors {
+			colors[i] = ui.ColorGreen
+			if i > 0 && i < len(prices) && prices[i] < prices[i-1] {
+				colors[i] = ui.ColorRed
+			}
+		}
+
+		page.VolumeChart.Data = volumes
+		page.VolumeChart.BarColors = colors
+	}
+
+	// updateMarketCap overlays market cap history on the price graph, scaled
+	// to the range of prices, while showMarketCap is set
+	updateMarketCap := func() {
+		if !showMarketCap || len(marketCapHistory) == 0 || len(priceHistory) == 0 {
+			delete(page.ValueGraph.Data, "Market Cap")
+			delete(page.ValueGraph.Labels, "Market Cap")
+			return
+		}
+
+		min := utils.MinFloat64(marketCapHistory...)
+		max := utils.MaxFloat64(marketCapHistory...)
+		priceRange := utils.MaxFloat64(priceHistory...)
+
+		scaled := make([]float64, len(marketCapHistory))
+		for i, val := range marketCapHistory {
+			if max > min {
+				scaled[i] = (val - min) / (max - min) * priceRange
+			}
+		}
+
+		vals, units := utils.RoundValues(marketCapHistory[len(marketCapHistory)-1], 0)
+		page.ValueGraph.Data["Market Cap"] = scaled
+		page.ValueGraph.Labels["Market Cap"] = fmt.Sprintf("%s %s %s", utils.FormatPrice(vals[0]), units, currency)
+	}
+
 	// UpdateUI to refresh UI
 	updateUI := func() {
 		// Get Terminal Dimensions
@@ -91,6 +267,9 @@ func DisplayCoin(
 
 		page.Grid.SetRect(0, 0, w, h)
 
+		// Fit volumes to the resized volume chart
+		updateVolumes()
+
 		// Clear UI
 		ui.Clear()
 
@@ -102,12 +281,24 @@ func DisplayCoin(
 		case uw.Portfolio:
 			portfolioTable.Resize(w, h)
 			ui.Render(portfolioTable)
+		case uw.Markets:
+			marketsTable.Resize(w, h)
+			ui.Render(marketsTable)
+		case uw.Activity:
+			activityTable.Resize(w, h)
+			ui.Render(activityTable)
+		case uw.Info:
+			infoPage.Resize(w, h)
+			ui.Render(infoPage)
 		case uw.Currency:
 			currencyWidget.Resize(w, h)
 			ui.Render(currencyWidget)
 		case uw.Change:
 			changeIntervalWidget.Resize(w, h)
 			ui.Render(changeIntervalWidget)
+		case uw.Error:
+			errorPage.Resize(w, h)
+			ui.Render(page.Grid, errorPage)
 		default:
 			ui.Render(page.Grid)
 		}
@@ -175,6 +366,44 @@ func DisplayCoin(
 					utilitySelected = uw.Change
 				}
 
+			case "r":
+				if utilitySelected == uw.None {
+					// Show history between dates entered, up to now if no
+					// end date is entered
+					from := readDate("From date, such as 2021-05-01", "")
+					if from.IsZero() {
+						break
+					}
+					to := readDate("To date, empty for now", "")
+					if !to.IsZero() && !to.After(from) {
+						break
+					}
+
+					rangeFrom, rangeTo = from, to
+					changeInterval = utils.FormatDateRange(rangeFrom, rangeTo)
+
+					// Empty current graphs
+					page.ValueGraph.Data["Value"] = []float64{}
+					page.CandleChart.Data = []widgets.Candle{}
+
+					sendHistoryOptions()
+				}
+
+			case "v":
+				if utilitySelected == uw.None {
+					// Toggle between line and candle view
+					candles = !candles
+					page.setGrid(candles)
+					sendHistoryOptions()
+				}
+
+			case "m":
+				if utilitySelected == uw.None {
+					// Toggle market cap overlay
+					showMarketCap = !showMarketCap
+					updateMarketCap()
+				}
+
 			case "f":
 				if utilitySelected == uw.None {
 					selectedTable.ShowCursor = false
@@ -194,10 +423,56 @@ func DisplayCoin(
 					selectedTable.ShowCursor = false
 					selectedTable = portfolioTable.Table
 					selectedTable.ShowCursor = true
-					portfolioTable.UpdateRows(portfolioMap, currency, currencyVal)
+					updatePortfolio()
+					fetchHoldings()
 					utilitySelected = uw.Portfolio
 				}
 
+			case "M":
+				if utilitySelected == uw.None {
+					selectedTable.ShowCursor = false
+					selectedTable = marketsTable.Table
+					selectedTable.ShowCursor = true
+					utilitySelected = uw.Markets
+				}
+
+			case "a":
+				if utilitySelected == uw.None {
+					selectedTable.ShowCursor = false
+					selectedTable = activityTable.Table
+					utilitySelected = uw.Activity
+				}
+
+			case "i":
+				if utilitySelected == uw.None {
+					selectedTable.ShowCursor = false
+					selectedTable = infoPage.Table
+					selectedTable.ShowCursor = true
+					utilitySelected = uw.Info
+				}
+
+			case "o":
+				if utilitySelected == uw.Info {
+					openLink()
+				}
+
+			case "y":
+				if utilitySelected == uw.Info {
+					// Copy the selected link or address
+					if text := infoPage.Selected(); text != "" {
+						if err := utils.CopyToClipboard(text); err != nil {
+							infoPage.SetStatus(err.Error())
+						} else {
+							infoPage.SetStatus("copied " + text)
+						}
+					}
+				}
+
+			case "E":
+				if utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+
 			// Navigations
 			case "j", "<Down>":
 				selectedTable.ScrollDown()
@@ -231,6 +506,9 @@ func DisplayCoin(
 			// Actions
 			case "<Enter>":
 				switch utilitySelected {
+				case uw.Info:
+					openLink()
+
 				case uw.Change:
 					// Update Graph Durations
 					if changeIntervalWidget.SelectedRow < len(changeIntervalWidget.Rows) {
@@ -238,13 +516,14 @@ func DisplayCoin(
 
 						// Get newer selected duration
 						changeInterval = row[0]
-						newChangeInterval := uw.IntervalMap[changeInterval]
+						rangeFrom, rangeTo = time.Time{}, time.Time{}
 
-						// Empty current graph
+						// Empty current graphs
 						page.ValueGraph.Data["Value"] = []float64{}
+						page.CandleChart.Data = []widgets.Candle{}
 
 						// Send Updated Interval
-						intervalChannel <- newChangeInterval
+						sendHistoryOptions()
 					}
 					utilitySelected = uw.None
 
@@ -257,9 +536,11 @@ func DisplayCoin(
 						// Get currency and rate
 						currencyID = row[0]
 						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+						vsCurrency.Set(currencyWidget.Code(currencyID))
 
 						// Update currency fields
 						favHeader[1] = fmt.Sprintf("Price (%s)", currency)
+						marketsTable.UpdateRows(tickers, currency, currencyVal)
 					}
 					utilitySelected = uw.None
 				}
@@ -276,15 +557,12 @@ func DisplayCoin(
 					id := ""
 					symbol := ""
 
-					// Get symbol
+					// Get ID and symbol
 					if portfolioTable.SelectedRow < len(portfolioTable.Rows) {
 						row := portfolioTable.Rows[portfolioTable.SelectedRow]
-						symbol = row[1]
+						id, symbol = row[5], row[1]
 					}
 
-					// Get ID from symbol
-					id = coinIDs[symbol].CoinGeckoID
-
 					if id != "" {
 						// Draw Edit Box and get new amount
 						inputStr := widgets.DrawEdit(uiEvents, symbol)
@@ -300,7 +578,21 @@ func DisplayCoin(
 						}
 					}
 
-					portfolioTable.UpdateRows(portfolioMap, currency, currencyVal)
+					updatePortfolio()
+				}
+			}
+
+			if utilitySelected == uw.Markets {
+				switch e.ID {
+				// Sort Ascending
+				case "1", "2", "3", "4", "5", "6", "7":
+					idx, _ := strconv.Atoi(e.ID)
+					marketsTable.Sort(idx-1, true)
+
+				// Sort Descending
+				case "<F1>", "<F2>", "<F3>", "<F4>", "<F5>", "<F6>", "<F7>":
+					idx, _ := strconv.Atoi(e.ID[2:3])
+					marketsTable.Sort(idx-1, false)
 				}
 			}
 
@@ -336,29 +628,64 @@ func DisplayCoin(
 				previousKey = e.ID
 			}
 
-		case data := <-priceChannel:
+		case <-prices.C:
+			updated := prices.Prices()
+			for id, p := range updated {
+				livePrices[id] = p
+			}
+
 			// Update live price
-			if data == "NA" {
+			if p, ok := updated[id]; ok {
+				page.PriceBox.Rows[0][0] = utils.FormatPrice(p / currencyVal)
 				if utilitySelected == uw.None {
-					page.PriceBox.Rows[0][0] = data
-				}
-			} else {
-				p, _ := strconv.ParseFloat(data, 64)
-				if utilitySelected == uw.None {
-					page.PriceBox.Rows[0][0] = fmt.Sprintf("%.2f", p/currencyVal)
 					ui.Render(page.PriceBox)
 				}
 			}
 
+			// Update favourite prices
+			for _, row := range page.FavouritesTable.Rows {
+				if p, ok := updated[row[2]]; ok {
+					row[1] = utils.FormatPrice(p / currencyVal)
+				}
+			}
+
 		case data := <-dataChannel:
+			if data.Err != nil {
+				if staleSince[data.Type].IsZero() && utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+				staleSince[data.Type] = errorPage.Fail(sources[data.Type], data.Err, staleSince[data.Type])
+				markStale()
+				break
+			}
+			delete(staleSince, data.Type)
+
+			// Skip data priced in a currency selected earlier
+			divisor, ok := vsCurrency.Divisor(data.VsCurrency, currencyVal)
+			if !ok {
+				break
+			}
+
 			switch data.Type {
 
 			case "FAVOURITES":
 				// Update favorites table
 				rows := [][]string{}
-				for symbol, price := range data.Favourites {
-					p := fmt.Sprintf("%.2f", price/currencyVal)
-					rows = append(rows, []string{symbol, p})
+				for coinID, price := range data.Favourites {
+					// Prefer the live price in USD
+					ids := coinIDs.Get(coinID)
+					price /= divisor
+					if live, ok := livePrices[coinID]; ok {
+						price = live / currencyVal
+					}
+
+					symbol := ids.Symbol
+					if symbol == "" {
+						symbol = strings.ToUpper(coinID)
+					}
+
+					p := utils.FormatPrice(price)
+					rows = append(rows, []string{symbol, p, coinID})
 				}
 				page.FavouritesTable.Header[1] = fmt.Sprintf("Price (%s)", currency)
 				page.FavouritesTable.Rows = rows
@@ -369,30 +696,72 @@ func DisplayCoin(
 
 				// Set value, min & max price
 				page.ValueGraph.Data["Value"] = price
-				value := (price[len(price)-1] + data.MinPrice) / currencyVal
-
-				page.ValueGraph.Labels["Value"] = fmt.Sprintf("%.2f %s", value, currency)
-				page.ValueGraph.Labels["Max"] = fmt.Sprintf("%.2f %s", data.MaxPrice/currencyVal, currency)
-				page.ValueGraph.Labels["Min"] = fmt.Sprintf("%.2f %s", data.MinPrice/currencyVal, currency)
+				if len(price) > 0 {
+					value := (price[len(price)-1] + data.MinPrice) / divisor
+					page.ValueGraph.Labels["Value"] = fmt.Sprintf("%s %s", utils.FormatPrice(value), currency)
+				} else {
+					page.ValueGraph.Labels["Value"] = ""
+				}
+				page.ValueGraph.Labels["Max"] = fmt.Sprintf("%s %s", utils.FormatPrice(data.MaxPrice/divisor), currency)
+				page.ValueGraph.Labels["Min"] = fmt.Sprintf("%s %s", utils.FormatPrice(data.MinPrice/divisor), currency)
 
 				// Update Graph title
 				page.ValueGraph.Title = fmt.Sprintf(" Value History (%s) ", changeInterval)
 
+				// Update volume and market cap history
+				priceHistory = price
+				volumeHistory = make([]float64, len(data.VolumeHistory))
+				for i, val := range data.VolumeHistory {
+					volumeHistory[i] = val / divisor
+				}
+				marketCapHistory = make([]float64, len(data.MarketCapHistory))
+				for i, val := range data.MarketCapHistory {
+					marketCapHistory[i] = val / divisor
+				}
+				updateVolumes()
+				updateMarketCap()
+
+				volume := "NA"
+				if len(volumeHistory) > 0 {
+					vals, units := utils.RoundValues(volumeHistory[len(volumeHistory)-1], 0)
+					volume = fmt.Sprintf("%s %s %s", utils.FormatPrice(vals[0]), units, currency)
+				}
+				page.VolumeChart.Title = fmt.Sprintf(" 24H Volume: %s ", volume)
+
+				// Update candles, sent only while they are shown
+				if data.OHLC != nil {
+					candleData := make([]widgets.Candle, len(data.OHLC))
+					for i, val := range data.OHLC {
+						candleData[i] = widgets.Candle{
+							Open:  val.Open / divisor,
+							High:  val.High / divisor,
+							Low:   val.Low / divisor,
+							Close: val.Close / divisor,
+						}
+					}
+					page.CandleChart.Data = candleData
+				}
+
+				for _, label := range []string{"Value", "Max", "Min"} {
+					page.CandleChart.Labels[label] = page.ValueGraph.Labels[label]
+				}
+				page.CandleChart.Title = fmt.Sprintf(" OHLC History (%s) ", changeInterval)
+
 			case "DETAILS":
 				// Update Details table
 				page.DetailsTable.Header = []string{"Name", data.Details.Name}
 
-				marketCapVals, units := utils.RoundValues(data.Details.MarketCap, 0)
-				marketCap := fmt.Sprintf("%.2f %s %s", marketCapVals[0]/currencyVal, units, currency)
+				marketCapVals, units := utils.RoundValues(data.Details.MarketCap/divisor, 0)
+				marketCap := fmt.Sprintf("%s %s %s", utils.FormatPrice(marketCapVals[0]), units, currency)
 
-				ATHVals, units := utils.RoundValues(data.Details.ATH, 0)
-				ATH := fmt.Sprintf("%.2f %s %s", ATHVals[0]/currencyVal, units, currency)
+				ATHVals, units := utils.RoundValues(data.Details.ATH/divisor, 0)
+				ATH := fmt.Sprintf("%s %s %s", utils.FormatPrice(ATHVals[0]), units, currency)
 
-				ATLVals, units := utils.RoundValues(data.Details.ATL, 0)
-				ATL := fmt.Sprintf("%.2f %s %s", ATLVals[0]/currencyVal, units, currency)
+				ATLVals, units := utils.RoundValues(data.Details.ATL/divisor, 0)
+				ATL := fmt.Sprintf("%s %s %s", utils.FormatPrice(ATLVals[0]), units, currency)
 
-				TotalVolVals, units := utils.RoundValues(data.Details.TotalVolume, 0)
-				TotalVolume := fmt.Sprintf("%.2f %s %s", TotalVolVals[0]/currencyVal, units, currency)
+				TotalVolVals, units := utils.RoundValues(data.Details.TotalVolume/divisor, 0)
+				TotalVolume := fmt.Sprintf("%s %s %s", utils.FormatPrice(TotalVolVals[0]), units, currency)
 
 				rows := [][]string{
 					{"Symbol", data.Details.Symbol},
@@ -410,8 +779,8 @@ func DisplayCoin(
 				page.DetailsTable.Rows = rows
 
 				// Update 24 High/Low
-				page.PriceBox.Rows[0][1] = fmt.Sprintf("%.2f", data.Details.High24/currencyVal)
-				page.PriceBox.Rows[0][2] = fmt.Sprintf("%.2f", data.Details.Low24/currencyVal)
+				page.PriceBox.Rows[0][1] = utils.FormatPrice(data.Details.High24 / divisor)
+				page.PriceBox.Rows[0][2] = utils.FormatPrice(data.Details.Low24 / divisor)
 				page.PriceBox.Title = fmt.Sprintf(" Live Price (%s) ", currency)
 
 				// Get Change Percents
@@ -428,6 +797,23 @@ func DisplayCoin(
 				// Get Explorers
 				page.ExplorerTable.Rows = data.Details.Explorers
 
+				// Update community and developer activity
+				activityTable.Update(data.Details.Activity, data.ActivitySince)
+
+				// Update description and links
+				infoPage.Update(data.Details)
+
+			case "PORTFOLIO":
+				// Update Portfolio table
+				holdings = data
+				updatePortfolio()
+
+			case "TICKERS":
+				// Update Markets table, tickers are only served in USD so
+				// they are converted with the USD rate of the currency
+				tickers = data.Tickers
+				marketsTable.UpdateRows(tickers, currency, currencyVal)
+
 			}
 
 			// Sort favourites table
@@ -443,7 +829,16 @@ func DisplayCoin(
 				utils.SortData(page.FavouritesTable.Rows, 0, true, "FAVOURITES")
 			}
 
+			markStale()
+
 		case <-tick: // Refresh UI
+			// Stream live prices of the coin and favourites
+			ids := []string{id}
+			for _, row := range page.FavouritesTable.Rows {
+				ids = append(ids, row[2])
+			}
+			priceHub.SetAssets("coin", ids)
+
 			updateUI()
 		}
 	}
@@ -26,6 +26,8 @@ type coinPage struct {
 	Grid            *ui.Grid
 	FavouritesTable *widgets.Table
 	ValueGraph      *widgets.LineGraph
+	CandleChart     *widgets.CandleChart
+	VolumeChart     *widgets.BarChart
 	DetailsTable    *widgets.Table
 	ChangesTable    *widgets.Table
 	PriceBox        *widgets.Table
@@ -39,6 +41,8 @@ func newCoinPage() *coinPage {
 		Grid:            ui.NewGrid(),
 		FavouritesTable: widgets.NewTable(),
 		ValueGraph:      widgets.NewLineGraph(),
+		CandleChart:     widgets.NewCandleChart(),
+		VolumeChart:     widgets.NewBarChart(),
 		DetailsTable:    widgets.NewTable(),
 		ChangesTable:    widgets.NewTable(),
 		PriceBox:        widgets.NewTable(),
@@ -65,6 +69,7 @@ func (page *coinPage) init() {
 		}
 	}
 	page.FavouritesTable.CursorColor = ui.ColorCyan
+	page.FavouritesTable.UniqueCol = 2
 
 	// Initialise Value Graph
 	page.ValueGraph.TitleStyle = ui.NewStyle(ui.ColorClear)
@@ -72,10 +77,27 @@ func (page *coinPage) init() {
 	page.ValueGraph.LineColors["Max"] = ui.ColorGreen
 	page.ValueGraph.LineColors["Min"] = ui.ColorRed
 	page.ValueGraph.LineColors["Value"] = ui.ColorBlue
+	page.ValueGraph.LineColors["Market Cap"] = ui.ColorYellow
 	page.ValueGraph.BorderStyle.Fg = ui.ColorCyan
 	page.ValueGraph.Data["Max"] = []float64{}
 	page.ValueGraph.Data["Min"] = []float64{}
 
+	// Initialise Candle Chart
+	page.CandleChart.Title = " OHLC History "
+	page.CandleChart.TitleStyle = ui.NewStyle(ui.ColorClear)
+	page.CandleChart.BorderStyle.Fg = ui.ColorCyan
+	page.CandleChart.LabelColors["Max"] = ui.ColorGreen
+	page.CandleChart.LabelColors["Min"] = ui.ColorRed
+	page.CandleChart.LabelColors["Value"] = ui.ColorBlue
+
+	// Initialise Volume Chart, with a bar per column and no numbers
+	page.VolumeChart.Title = " 24H Volume "
+	page.VolumeChart.BorderStyle.Fg = ui.ColorCyan
+	page.VolumeChart.TitleStyle.Fg = ui.ColorClear
+	page.VolumeChart.BarWidth = 1
+	page.VolumeChart.BarGap = 0
+	page.VolumeChart.NumFormatter = func(float64) string { return "" }
+
 	// Initialise Details Table
 	page.DetailsTable.Title = " Details "
 	page.DetailsTable.BorderStyle.Fg = ui.ColorCyan
@@ -142,15 +164,29 @@ func (page *coinPage) init() {
 	page.SupplyChart.LabelStyles = []ui.Style{ui.NewStyle(ui.ColorClear)}
 	page.SupplyChart.NumStyles = []ui.Style{ui.NewStyle(ui.ColorBlack)}
 
+	page.setGrid(false)
+}
+
+// setGrid lays out the widgets of a coinPage, showing price history as
+// candles if candles is set or as a line graph otherwise, with volumes
+// below
+func (page *coinPage) setGrid(candles bool) {
+	var history interface{} = page.ValueGraph
+	if candles {
+		history = page.CandleChart
+	}
+
 	// Set Grid layout
 	w, h := ui.TerminalDimensions()
+	page.Grid.Items = nil
 	page.Grid.Set(
 		ui.NewCol(0.33,
 			ui.NewRow(0.5, page.FavouritesTable),
 			ui.NewRow(0.5, page.DetailsTable),
 		),
 		ui.NewCol(0.67,
-			ui.NewRow(0.5, page.ValueGraph),
+			ui.NewRow(0.3, history),
+			ui.NewRow(0.2, page.VolumeChart),
 			ui.NewRow(0.5,
 				ui.NewCol(0.5,
 					ui.NewRow(0.4, page.PriceBox),
@@ -0,0 +1,104 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package coin
+
+import (
+	"context"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	ui "github.com/gizak/termui/v3"
+	"golang.org/x/sync/errgroup"
+)
+
+// Show serves data of the coin specified by its CoinGecko id, priced in the
+// currency specified by code, and displays the coin page until it is closed.
+// Prices of favourites are served along with it. Closing the page is not
+// an error.
+func Show(
+	ctx context.Context,
+	id string,
+	code string,
+	coinIDs *api.CoinIDMap,
+	favourites map[string]bool,
+	priceHub *api.PriceHub,
+	uiEvents <-chan ui.Event) error {
+
+	// Create new errorgroup for coin page
+	eg, coinCtx := errgroup.WithContext(ctx)
+	coinDataChannel := make(chan api.CoinData)
+	historyChannel := make(chan api.HistoryOptions)
+	coinVsCurrency := api.NewCurrency()
+	coinVsCurrency.Set(code)
+
+	// Clear UI
+	ui.Clear()
+
+	// Serve Coin Price History
+	eg.Go(func() error {
+		err := api.GetCoinHistory(
+			coinCtx,
+			id,
+			coinVsCurrency,
+			historyChannel,
+			coinDataChannel,
+		)
+		return err
+	})
+
+	// Serve Coin Asset data
+	eg.Go(func() error {
+		err := api.GetCoinDetails(coinCtx, id, coinVsCurrency, coinDataChannel)
+		return err
+	})
+
+	// Serve exchanges and pairs the coin trades in
+	eg.Go(func() error {
+		err := api.GetCoinTickers(coinCtx, id, coinDataChannel)
+		return err
+	})
+
+	// Serve favourite coin prices
+	eg.Go(func() error {
+		err := api.GetFavouritePrices(coinCtx,
+			favourites,
+			coinVsCurrency,
+			coinDataChannel,
+		)
+		return err
+	})
+
+	// Serve Visuals for coin
+	eg.Go(func() error {
+		err := DisplayCoin(
+			coinCtx,
+			id,
+			coinIDs,
+			coinVsCurrency,
+			historyChannel,
+			coinDataChannel,
+			priceHub,
+			uiEvents,
+		)
+		return err
+	})
+
+	if err := eg.Wait(); err != nil && err.Error() != "UI Closed" {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compare
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// DisplayCompare overlays the price history of coins, as percent change from
+// the start of the selected interval, served on dataChannel in the order of
+// coins. Selected intervals are sent on intervalChannel. It uses the same
+// uiEvents channel as the root page.
+func DisplayCompare(
+	ctx context.Context,
+	coins []api.CoinID,
+	intervalChannel chan string,
+	dataChannel chan api.CompareData,
+	uiEvents <-chan ui.Event) error {
+
+	defer ui.Clear()
+
+	// Init Compare page
+	page := newComparePage()
+
+	// Name series by symbol, telling apart coins which share one
+	names := make([]string, len(coins))
+	used := make(map[string]bool)
+	for i, coin := range coins {
+		name := coin.Symbol
+		if name == "" || used[name] {
+			name = fmt.Sprintf("%s (%s)", coin.Symbol, coin.CoinGeckoID)
+		}
+		used[name] = true
+		names[i] = name
+		page.ChangeGraph.LineColors[name] = lineColors[i%len(lineColors)]
+	}
+
+	// Variables for the interval
+	changeInterval := "90 Days"
+	changeIntervalWidget := uw.NewChangeIntervalPage()
+
+	// Selection of default table
+	selectedTable := page.ChangeTable
+	utilitySelected := uw.None
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("COMPARE")
+
+	// Initialise error page and time since which history is stale, which is
+	// zero while it is up to date
+	errorPage := uw.NewErrorPage()
+	staleSince := time.Time{}
+
+	// Last changes received, kept to draw them again when resized
+	changes := [][]float64{}
+
+	// updateGraph overlays changes, merging points so the whole interval
+	// fits the graph. Changes are shifted up by the lowest, as the graph
+	// only draws values from 0.
+	updateGraph := func() {
+		page.ChangeGraph.Title = utils.StaleTitle(fmt.Sprintf(" Change %% (%s) ", changeInterval), staleSince)
+		if len(changes) == 0 {
+			return
+		}
+
+		longest, min := 0, 0.0
+		for _, change := range changes {
+			if len(change) > longest {
+				longest = len(change)
+			}
+			min = utils.MinFloat64(append([]float64{min}, change...)...)
+		}
+
+		// Histories end together, so points are taken every step from the
+		// latest to keep them aligned
+		width := 2 * page.ChangeGraph.Inner.Dx()
+		if width < 1 {
+			width = 1
+		}
+		step := (longest + width - 1) / width
+
+		for i, change := range changes {
+			points := []float64{}
+			for j := len(change) - 1; j >= 0; j -= step {
+				points = append([]float64{change[j] - min}, points...)
+			}
+
+			page.ChangeGraph.Data[names[i]] = points
+			page.ChangeGraph.Labels[names[i]] = fmt.Sprintf("%+.2f%%", change[len(change)-1])
+		}
+	}
+
+	// updateRows lists the coins by change over the interval, largest first
+	updateRows := func() {
+		order := make([]int, len(changes))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			a, b := changes[order[i]], changes[order[j]]
+			return a[len(a)-1] > b[len(b)-1]
+		})
+
+		rows := [][]string{}
+		for _, i := range order {
+			change := changes[i][len(changes[i])-1]
+			changeStr := fmt.Sprintf("%s %.2f", utils.UpArrow, change)
+			if change < 0 {
+				changeStr = fmt.Sprintf("%s %.2f", utils.DownArrow, -change)
+			}
+
+			rows = append(rows, []string{
+				names[i],
+				coins[i].Name,
+				changeStr,
+				fmt.Sprintf("%+.2f", utils.MaxFloat64(changes[i]...)),
+				fmt.Sprintf("%+.2f", utils.MinFloat64(changes[i]...)),
+			})
+		}
+
+		page.ChangeTable.Rows = rows
+		page.ChangeTable.Title = utils.StaleTitle(fmt.Sprintf(" Coins (%s) ", changeInterval), staleSince)
+	}
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+		updateGraph()
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case uw.Help:
+			help.Resize(w, h)
+			ui.Render(help)
+		case uw.Change:
+			changeIntervalWidget.Resize(w, h)
+			ui.Render(changeIntervalWidget)
+		case uw.Error:
+			errorPage.Resize(w, h)
+			ui.Render(page.Grid, errorPage)
+		default:
+			ui.Render(page.Grid)
+		}
+	}
+
+	// Render empty UI
+	updateUI()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(time.Duration(1) * time.Second)
+	tick := t.C
+
+	previousKey := ""
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case e := <-uiEvents: // keyboard events
+			switch e.ID {
+			case "<Escape>", "q", "<C-c>":
+				if utilitySelected != uw.None {
+					utilitySelected = uw.None
+					selectedTable = page.ChangeTable
+					selectedTable.ShowCursor = true
+					updateUI()
+				} else {
+					return fmt.Errorf("UI Closed")
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "?":
+				selectedTable.ShowCursor = false
+				selectedTable = help.Table
+				selectedTable.ShowCursor = true
+				utilitySelected = uw.Help
+
+			case "d":
+				if utilitySelected == uw.None {
+					selectedTable.ShowCursor = false
+					selectedTable = changeIntervalWidget.Table
+					selectedTable.ShowCursor = true
+					utilitySelected = uw.Change
+				}
+
+			case "E":
+				if utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+
+			// Navigations
+			case "j", "<Down>":
+				selectedTable.ScrollDown()
+
+			case "k", "<Up>":
+				selectedTable.ScrollUp()
+
+			case "g":
+				if previousKey == "g" {
+					selectedTable.ScrollTop()
+				}
+
+			case "<Home>":
+				selectedTable.ScrollTop()
+
+			case "G", "<End>":
+				selectedTable.ScrollBottom()
+
+			// Actions
+			case "<Enter>":
+				if utilitySelected == uw.Change {
+					if changeIntervalWidget.SelectedRow < len(changeIntervalWidget.Rows) {
+						changeInterval = changeIntervalWidget.Rows[changeIntervalWidget.SelectedRow][0]
+
+						// Empty current graph and send updated interval
+						changes = [][]float64{}
+						page.ChangeGraph.Data = make(map[string][]float64)
+						page.ChangeGraph.Labels = make(map[string]string)
+						page.ChangeTable.Rows = [][]string{}
+
+						select {
+						case <-ctx.Done():
+							return ctx.Err()
+						case intervalChannel <- uw.IntervalMap[changeInterval]:
+						}
+					}
+					utilitySelected = uw.None
+					selectedTable = page.ChangeTable
+					selectedTable.ShowCursor = true
+				}
+			}
+
+			updateUI()
+			if previousKey == "g" {
+				previousKey = ""
+			} else {
+				previousKey = e.ID
+			}
+
+		case data := <-dataChannel:
+			if data.Err != nil {
+				if staleSince.IsZero() && utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+				staleSince = errorPage.Fail("History", data.Err, staleSince)
+				page.ChangeGraph.Title = utils.StaleTitle(page.ChangeGraph.Title, staleSince)
+				page.ChangeTable.Title = utils.StaleTitle(page.ChangeTable.Title, staleSince)
+				break
+			}
+
+			// Skip history of an interval selected earlier
+			if data.Interval != uw.IntervalMap[changeInterval] {
+				break
+			}
+			staleSince = time.Time{}
+
+			changes = data.Changes
+			updateGraph()
+			updateRows()
+
+		case <-tick: // Refresh UI
+			updateUI()
+		}
+	}
+}
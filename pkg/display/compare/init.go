@@ -0,0 +1,88 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compare
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// lineColors holds the colours of the coins compared, in order
+var lineColors = []ui.Color{
+	ui.ColorBlue,
+	ui.ColorGreen,
+	ui.ColorRed,
+	ui.ColorYellow,
+	ui.ColorMagenta,
+	ui.ColorCyan,
+}
+
+// comparePage holds UI items for the coin comparison page
+type comparePage struct {
+	Grid        *ui.Grid
+	ChangeGraph *widgets.LineGraph
+	ChangeTable *widgets.Table
+}
+
+// newComparePage creates, initialises and returns a pointer to an instance of comparePage
+func newComparePage() *comparePage {
+	page := &comparePage{
+		Grid:        ui.NewGrid(),
+		ChangeGraph: widgets.NewLineGraph(),
+		ChangeTable: widgets.NewTable(),
+	}
+	page.init()
+
+	return page
+}
+
+// init initialises the widgets of a comparePage
+func (page *comparePage) init() {
+	// Initialise Change Graph
+	page.ChangeGraph.Title = " Change % "
+	page.ChangeGraph.TitleStyle = ui.NewStyle(ui.ColorClear)
+	page.ChangeGraph.HorizontalScale = 1
+	page.ChangeGraph.BorderStyle.Fg = ui.ColorCyan
+
+	// Initialise Change Table
+	page.ChangeTable.Title = " Coins "
+	page.ChangeTable.BorderStyle.Fg = ui.ColorCyan
+	page.ChangeTable.TitleStyle.Fg = ui.ColorClear
+	page.ChangeTable.Header = []string{"Symbol", "Name", "Change %", "High %", "Low %"}
+	page.ChangeTable.ColResizer = func() {
+		x := page.ChangeTable.Inner.Dx()
+		page.ChangeTable.ColWidths = []int{
+			x / 6,
+			3 * x / 10,
+			x / 6,
+			x / 6,
+			x / 6,
+		}
+	}
+	page.ChangeTable.CursorColor = ui.ColorCyan
+	page.ChangeTable.ChangeCol[2] = true
+	page.ChangeTable.ShowCursor = true
+
+	// Set Grid layout
+	w, h := ui.TerminalDimensions()
+	page.Grid.Set(
+		ui.NewRow(0.7, page.ChangeGraph),
+		ui.NewRow(0.3, page.ChangeTable),
+	)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
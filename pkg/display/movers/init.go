@@ -0,0 +1,88 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package movers
+
+import (
+	"fmt"
+
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// moversPage holds UI items for the top gainers and losers page, with a
+// gainers and a losers table for each duration in uw.Durations
+type moversPage struct {
+	Grid          *ui.Grid
+	GainersTables []*widgets.Table
+	LosersTables  []*widgets.Table
+}
+
+// newMoversPage creates, initialises and returns a pointer to an instance of moversPage
+func newMoversPage() *moversPage {
+	page := &moversPage{
+		Grid: ui.NewGrid(),
+	}
+	for range uw.Durations {
+		page.GainersTables = append(page.GainersTables, widgets.NewTable())
+		page.LosersTables = append(page.LosersTables, widgets.NewTable())
+	}
+	page.init()
+
+	return page
+}
+
+// init initialises the widgets of a moversPage
+func (page *moversPage) init() {
+	rows := []interface{}{}
+	for i, duration := range uw.Durations {
+		for _, table := range []*widgets.Table{page.GainersTables[i], page.LosersTables[i]} {
+			table := table
+			table.BorderStyle.Fg = ui.ColorCyan
+			table.TitleStyle.Fg = ui.ColorClear
+			table.Header = []string{"Rank", "Symbol", "Price", "Change %", "Market Cap", "Volume"}
+			table.ColResizer = func() {
+				x := table.Inner.Dx()
+				table.ColWidths = []int{
+					x / 8,
+					x / 6,
+					x / 5,
+					x / 6,
+					x / 6,
+					x / 6,
+				}
+			}
+			table.CursorColor = ui.ColorCyan
+			table.ChangeCol[3] = true
+			table.UniqueCol = 6
+		}
+
+		page.GainersTables[i].Title = fmt.Sprintf(" Top Gainers (%s) ", duration)
+		page.LosersTables[i].Title = fmt.Sprintf(" Top Losers (%s) ", duration)
+
+		rows = append(rows, ui.NewRow(1.0/float64(len(uw.Durations)),
+			ui.NewCol(0.5, page.GainersTables[i]),
+			ui.NewCol(0.5, page.LosersTables[i]),
+		))
+	}
+
+	// Set Grid layout
+	w, h := ui.TerminalDimensions()
+	page.Grid.Set(rows...)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
@@ -0,0 +1,365 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package movers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/display/coin"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// topN is the number of gainers and losers listed
+const topN = 25
+
+// DisplayMovers displays the top gainers and losers among coins served on
+// dataChannel, the channel serving the main page, over each duration.
+// Coins can be filtered by a minimum market cap and volume. It uses the same
+// uiEvents channel as the root page, and sendData is unset while a coin page
+// opened from it is shown.
+func DisplayMovers(
+	ctx context.Context,
+	dataChannel chan api.AssetData,
+	vsCurrency *api.Currency,
+	coinIDs *api.CoinIDMap,
+	priceHub *api.PriceHub,
+	sendData *bool,
+	uiEvents <-chan ui.Event) error {
+
+	defer ui.Clear()
+
+	// Init Movers page
+	page := newMoversPage()
+
+	// Currency table
+	currencyWidget := uw.NewCurrencyPage()
+
+	currencyID := utils.GetCurrencyID()
+	currencyID, currency, currencyVal := currencyWidget.Get(currencyID)
+	vsCurrency.Set(currencyWidget.Code(currencyID))
+
+	// Filters in the selected currency, 0 when unset
+	minMarketCap := 0.0
+	minVolume := 0.0
+
+	// Selection of default table, the gainers over 24 hours
+	selectedDuration := 1
+	selectedGainers := true
+	selectedTable := page.GainersTables[selectedDuration]
+	selectedTable.ShowCursor = true
+	utilitySelected := uw.None
+
+	// focusTable moves the cursor to the gainers or losers table of the
+	// selected duration
+	focusTable := func() {
+		selectedTable.ShowCursor = false
+		selectedTable = page.LosersTables[selectedDuration]
+		if selectedGainers {
+			selectedTable = page.GainersTables[selectedDuration]
+		}
+		selectedTable.ShowCursor = true
+	}
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("MOVERS")
+
+	// Initialise error page and time since which coin data is stale,
+	// which is zero while data is up to date
+	errorPage := uw.NewErrorPage()
+	staleSince := time.Time{}
+
+	// Last coins received, kept to list them again when the duration or
+	// filters change
+	data := api.AssetData{}
+
+	// updateRows lists the coins passing the filters with the largest rises
+	// and falls over each duration. Favourites and coins held outside the
+	// top coins are left out.
+	updateRows := func() {
+		divisor, ok := vsCurrency.Divisor(data.VsCurrency, currencyVal)
+		if !ok {
+			return
+		}
+
+		// Show filters in titles
+		filters := []string{}
+		if minMarketCap > 0 {
+			filters = append(filters, fmt.Sprintf("Cap >= %s %s", utils.FormatAmount(minMarketCap), currency))
+		}
+		if minVolume > 0 {
+			filters = append(filters, fmt.Sprintf("Volume >= %s %s", utils.FormatAmount(minVolume), currency))
+		}
+		suffix := ""
+		if len(filters) > 0 {
+			suffix = ", " + strings.Join(filters, ", ")
+		}
+
+		for i, changePercent := range uw.Durations {
+			duration := uw.DurationMap[changePercent]
+			movers := []api.CoinMarket{}
+			for _, val := range data.Ranked {
+				if _, ok := val.PriceChangePercentage[duration]; !ok {
+					continue
+				}
+				if val.MarketCap/divisor < minMarketCap || val.TotalVolume/divisor < minVolume {
+					continue
+				}
+				movers = append(movers, val)
+			}
+
+			sort.SliceStable(movers, func(i, j int) bool {
+				return movers[i].PriceChangePercentage[duration] > movers[j].PriceChangePercentage[duration]
+			})
+
+			toRow := func(val api.CoinMarket) []string {
+				change := val.PriceChangePercentage[duration]
+				changeStr := fmt.Sprintf("%s %.2f", utils.UpArrow, change)
+				if change < 0 {
+					changeStr = fmt.Sprintf("%s %.2f", utils.DownArrow, -change)
+				}
+
+				return []string{
+					fmt.Sprintf("%d", val.MarketCapRank),
+					strings.ToUpper(val.Symbol),
+					utils.FormatPrice(val.CurrentPrice / divisor),
+					changeStr,
+					utils.FormatAmount(val.MarketCap / divisor),
+					utils.FormatAmount(val.TotalVolume / divisor),
+					val.ID, // not displayed, identifies the coin
+				}
+			}
+
+			gainers := [][]string{}
+			for i := 0; i < len(movers) && len(gainers) < topN; i++ {
+				if movers[i].PriceChangePercentage[duration] > 0 {
+					gainers = append(gainers, toRow(movers[i]))
+				}
+			}
+
+			losers := [][]string{}
+			for i := len(movers) - 1; i >= 0 && len(losers) < topN; i-- {
+				if movers[i].PriceChangePercentage[duration] < 0 {
+					losers = append(losers, toRow(movers[i]))
+				}
+			}
+
+			page.GainersTables[i].Rows = gainers
+			page.LosersTables[i].Rows = losers
+
+			for _, table := range []*widgets.Table{page.GainersTables[i], page.LosersTables[i]} {
+				table.Header[2] = fmt.Sprintf("Price (%s)", currency)
+			}
+			page.GainersTables[i].Title = utils.StaleTitle(fmt.Sprintf(" Top Gainers (%s%s) ", changePercent, suffix), staleSince)
+			page.LosersTables[i].Title = utils.StaleTitle(fmt.Sprintf(" Top Losers (%s%s) ", changePercent, suffix), staleSince)
+		}
+	}
+
+	// readAmount asks for an amount in the selected currency, an empty or
+	// invalid amount clears the filter
+	readAmount := func(name string) float64 {
+		title := fmt.Sprintf("Minimum %s in %s, such as 10M", name, currency)
+		amount, err := utils.ParseAmount(widgets.DrawPrompt(uiEvents, title, ""))
+		if err != nil || amount < 0 {
+			return 0
+		}
+		return amount
+	}
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case uw.Help:
+			help.Resize(w, h)
+			ui.Render(help)
+		case uw.Error:
+			errorPage.Resize(w, h)
+			ui.Render(page.Grid, errorPage)
+		default:
+			ui.Render(page.Grid)
+		}
+	}
+
+	// Render empty UI
+	updateUI()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(time.Duration(1) * time.Second)
+	tick := t.C
+
+	previousKey := ""
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case e := <-uiEvents: // keyboard events
+			switch e.ID {
+			case "<Escape>", "q", "<C-c>":
+				if utilitySelected != uw.None {
+					utilitySelected = uw.None
+					focusTable()
+					updateUI()
+				} else {
+					return fmt.Errorf("UI Closed")
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "?":
+				selectedTable.ShowCursor = false
+				selectedTable = help.Table
+				selectedTable.ShowCursor = true
+				utilitySelected = uw.Help
+
+			case "m":
+				if utilitySelected == uw.None {
+					minMarketCap = readAmount("market cap")
+					updateRows()
+				}
+
+			case "v":
+				if utilitySelected == uw.None {
+					minVolume = readAmount("volume")
+					updateRows()
+				}
+
+			case "h", "<Left>":
+				if utilitySelected == uw.None {
+					selectedGainers = true
+					focusTable()
+				}
+
+			case "l", "<Right>":
+				if utilitySelected == uw.None {
+					selectedGainers = false
+					focusTable()
+				}
+
+			case "<Tab>":
+				if utilitySelected == uw.None {
+					selectedDuration = (selectedDuration + 1) % len(uw.Durations)
+					focusTable()
+				}
+
+			case "E":
+				if utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+
+			// Navigations
+			case "j", "<Down>":
+				selectedTable.ScrollDown()
+
+			case "k", "<Up>":
+				selectedTable.ScrollUp()
+
+			case "<C-d>":
+				selectedTable.ScrollHalfPageDown()
+
+			case "<C-u>":
+				selectedTable.ScrollHalfPageUp()
+
+			case "<C-f>":
+				selectedTable.ScrollPageDown()
+
+			case "<C-b>":
+				selectedTable.ScrollPageUp()
+
+			case "g":
+				if previousKey == "g" {
+					selectedTable.ScrollTop()
+				}
+
+			case "<Home>":
+				selectedTable.ScrollTop()
+
+			case "G", "<End>":
+				selectedTable.ScrollBottom()
+
+			// Actions
+			case "<Enter>":
+				switch utilitySelected {
+				case uw.None:
+					id := ""
+					if selectedTable.SelectedRow < len(selectedTable.Rows) {
+						id = selectedTable.Rows[selectedTable.SelectedRow][6]
+					}
+
+					if id != "" {
+						// Pause coin data while the coin page is shown
+						*sendData = false
+						err := coin.Show(ctx, id, currencyWidget.Code(currencyID), coinIDs, utils.GetFavourites(), priceHub, uiEvents)
+						*sendData = true
+						if err != nil {
+							return err
+						}
+
+						currencyID = utils.GetCurrencyID()
+						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+						vsCurrency.Set(currencyWidget.Code(currencyID))
+					}
+				}
+			}
+
+			updateUI()
+			if previousKey == "g" {
+				previousKey = ""
+			} else {
+				previousKey = e.ID
+			}
+
+		case newData := <-dataChannel:
+			if newData.Err != nil {
+				if staleSince.IsZero() && utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+				staleSince = errorPage.Fail("Coin", newData.Err, staleSince)
+				for i := range uw.Durations {
+					page.GainersTables[i].Title = utils.StaleTitle(page.GainersTables[i].Title, staleSince)
+					page.LosersTables[i].Title = utils.StaleTitle(page.LosersTables[i].Title, staleSince)
+				}
+				break
+			}
+			staleSince = time.Time{}
+
+			data = newData
+			updateRows()
+
+		case <-tick: // Refresh UI
+			updateUI()
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overview
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// overviewPage holds UI items for the global market overview page
+type overviewPage struct {
+	Grid            *ui.Grid
+	SummaryTable    *widgets.Table
+	BreadthTable    *widgets.Table
+	DominanceGraphs map[string]*widgets.LineGraph
+}
+
+// newOverviewPage creates, initialises and returns a pointer to an instance of overviewPage
+func newOverviewPage() *overviewPage {
+	page := &overviewPage{
+		Grid:         ui.NewGrid(),
+		SummaryTable: widgets.NewTable(),
+		BreadthTable: widgets.NewTable(),
+		DominanceGraphs: map[string]*widgets.LineGraph{
+			"BTC": widgets.NewLineGraph(),
+			"ETH": widgets.NewLineGraph(),
+		},
+	}
+	page.init()
+
+	return page
+}
+
+// init initialises the widgets of an overviewPage
+func (page *overviewPage) init() {
+	// Initialise Summary Table
+	page.SummaryTable.Title = " Global Market "
+	page.SummaryTable.BorderStyle.Fg = ui.ColorCyan
+	page.SummaryTable.TitleStyle.Fg = ui.ColorClear
+	page.SummaryTable.Header = []string{"Statistic", "Value"}
+	page.SummaryTable.ColResizer = func() {
+		x := page.SummaryTable.Inner.Dx()
+		page.SummaryTable.ColWidths = []int{
+			4 * x / 10,
+			6 * x / 10,
+		}
+	}
+	page.SummaryTable.ShowCursor = false
+
+	// Initialise Breadth Table
+	page.BreadthTable.Title = " Market Breadth "
+	page.BreadthTable.BorderStyle.Fg = ui.ColorCyan
+	page.BreadthTable.TitleStyle.Fg = ui.ColorClear
+	page.BreadthTable.Header = []string{"Duration", "Advancers", "Decliners", "Median Change %"}
+	page.BreadthTable.ColResizer = func() {
+		x := page.BreadthTable.Inner.Dx()
+		page.BreadthTable.ColWidths = []int{
+			x / 4,
+			x / 4,
+			x / 4,
+			x / 4,
+		}
+	}
+	page.BreadthTable.ColColor[1] = ui.ColorGreen
+	page.BreadthTable.ColColor[2] = ui.ColorRed
+	page.BreadthTable.ChangeCol[3] = true
+	page.BreadthTable.ShowCursor = false
+
+	// Initialise Dominance Graphs
+	for symbol, graph := range page.DominanceGraphs {
+		graph.Title = " " + symbol + " Dominance "
+		graph.TitleStyle = ui.NewStyle(ui.ColorClear)
+		graph.HorizontalScale = 1
+		graph.LineColors["Max"] = ui.ColorGreen
+		graph.LineColors["Min"] = ui.ColorRed
+		graph.LineColors["Value"] = ui.ColorBlue
+		graph.BorderStyle.Fg = ui.ColorCyan
+		graph.Data["Max"] = []float64{}
+		graph.Data["Min"] = []float64{}
+	}
+
+	// Set Grid layout
+	w, h := ui.TerminalDimensions()
+	page.Grid.Set(
+		ui.NewRow(0.4,
+			ui.NewCol(0.5, page.SummaryTable),
+			ui.NewCol(0.5, page.BreadthTable),
+		),
+		ui.NewRow(0.6,
+			ui.NewCol(0.5, page.DominanceGraphs["BTC"]),
+			ui.NewCol(0.5, page.DominanceGraphs["ETH"]),
+		),
+	)
+
+	page.Grid.SetRect(0, 0, w, h)
+}
@@ -0,0 +1,309 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overview
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	uw "github.com/Gituser143/cryptgo/pkg/display/utilitywidgets"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// DisplayOverview displays market wide totals and dominance served on
+// globalChannel, along with market breadth computed from coins served on
+// assetChannel, the channel serving the main page. It uses the same uiEvents channel as the root page. The
+// currency selected is set in vsCurrency for the data to be fetched in.
+func DisplayOverview(
+	ctx context.Context,
+	vsCurrency *api.Currency,
+	globalChannel chan api.GlobalData,
+	assetChannel chan api.AssetData,
+	uiEvents <-chan ui.Event) error {
+
+	defer ui.Clear()
+
+	// Init Overview page
+	page := newOverviewPage()
+
+	// Currency table
+	currencyWidget := uw.NewCurrencyPage()
+
+	currencyID := utils.GetCurrencyID()
+	currencyID, currency, currencyVal := currencyWidget.Get(currencyID)
+	vsCurrency.Set(currencyWidget.Code(currencyID))
+
+	// Selection of default table
+	selectedTable := page.BreadthTable
+	utilitySelected := uw.None
+
+	// Initialise help menu
+	help := widgets.NewHelpMenu()
+	help.SelectHelpMenu("OVERVIEW")
+
+	// Initialise error page and times since which each type of data is
+	// stale, types missing from staleSince are up to date
+	errorPage := uw.NewErrorPage()
+	staleSince := map[string]time.Time{}
+
+	// markStale marks titles of widgets showing stale data
+	markStale := func() {
+		page.SummaryTable.Title = utils.StaleTitle(page.SummaryTable.Title, staleSince["GLOBAL"])
+		page.BreadthTable.Title = utils.StaleTitle(page.BreadthTable.Title, staleSince["COINS"])
+		for _, graph := range page.DominanceGraphs {
+			graph.Title = utils.StaleTitle(graph.Title, staleSince["GLOBAL"])
+		}
+	}
+
+	// reportError records err met fetching data of the given type, keeping
+	// the last data received on screen
+	reportError := func(dataType, source string, err error) {
+		if staleSince[dataType].IsZero() && utilitySelected == uw.None {
+			utilitySelected = uw.Error
+		}
+		staleSince[dataType] = errorPage.Fail(source, err, staleSince[dataType])
+		markStale()
+	}
+
+	// Dominance recorded so far
+	dominanceHistory := []api.DominanceSample{}
+
+	// updateDominance fits dominance history to the width of the graphs
+	updateDominance := func() {
+		if len(dominanceHistory) == 0 {
+			return
+		}
+
+		for symbol, graph := range page.DominanceGraphs {
+			values := make([]float64, len(dominanceHistory))
+			for i, sample := range dominanceHistory {
+				values[i] = sample.BTC
+				if symbol == "ETH" {
+					values[i] = sample.ETH
+				}
+			}
+
+			// Braille characters are 2 dots wide
+			values = utils.Resample(values, graph.Inner.Dx()*2)
+			if len(values) == 0 {
+				continue
+			}
+
+			min := utils.MinFloat64(values...)
+			max := utils.MaxFloat64(values...)
+			value := values[len(values)-1]
+
+			// Clean data for graph
+			for i := range values {
+				values[i] -= min
+			}
+
+			graph.Data["Value"] = values
+			graph.Labels["Value"] = fmt.Sprintf("%.2f%%", value)
+			graph.Labels["Max"] = fmt.Sprintf("%.2f%%", max)
+			graph.Labels["Min"] = fmt.Sprintf("%.2f%%", min)
+			graph.Title = fmt.Sprintf(" %s Dominance (sampled while open, since %s) ", symbol, dominanceHistory[0].Time.Format("02 Jan 15:04"))
+		}
+	}
+
+	// UpdateUI to refresh UI
+	updateUI := func() {
+		// Get Terminal Dimensions
+		w, h := ui.TerminalDimensions()
+		page.Grid.SetRect(0, 0, w, h)
+
+		// Fit dominance to the resized graphs
+		updateDominance()
+		markStale()
+
+		// Clear UI
+		ui.Clear()
+
+		// Render required widgets
+		switch utilitySelected {
+		case uw.Help:
+			help.Resize(w, h)
+			ui.Render(help)
+		case uw.Error:
+			errorPage.Resize(w, h)
+			ui.Render(page.Grid, errorPage)
+		default:
+			ui.Render(page.Grid)
+		}
+	}
+
+	// Render empty UI
+	updateUI()
+
+	// Create ticker to periodically refresh UI
+	t := time.NewTicker(time.Duration(1) * time.Second)
+	tick := t.C
+
+	previousKey := ""
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return ctx.Err()
+
+		case e := <-uiEvents: // keyboard events
+			switch e.ID {
+			case "<Escape>", "q", "<C-c>":
+				if utilitySelected != uw.None {
+					utilitySelected = uw.None
+					selectedTable = page.BreadthTable
+					updateUI()
+				} else {
+					return fmt.Errorf("UI Closed")
+				}
+
+			case "<Resize>":
+				updateUI()
+
+			case "?":
+				selectedTable = help.Table
+				selectedTable.ShowCursor = true
+				utilitySelected = uw.Help
+
+			case "E":
+				if utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+
+			// Navigations
+			case "j", "<Down>":
+				selectedTable.ScrollDown()
+
+			case "k", "<Up>":
+				selectedTable.ScrollUp()
+
+			case "g":
+				if previousKey == "g" {
+					selectedTable.ScrollTop()
+				}
+
+			case "<Home>":
+				selectedTable.ScrollTop()
+
+			case "G", "<End>":
+				selectedTable.ScrollBottom()
+			}
+
+			updateUI()
+			if previousKey == "g" {
+				previousKey = ""
+			} else {
+				previousKey = e.ID
+			}
+
+		case data := <-globalChannel:
+			if data.Err != nil {
+				reportError("GLOBAL", "Global market", data.Err)
+				break
+			}
+			delete(staleSince, "GLOBAL")
+
+			// Skip data priced in a currency selected earlier
+			divisor, ok := vsCurrency.Divisor(data.VsCurrency, currencyVal)
+			if !ok {
+				break
+			}
+
+			marketCapVals, units := utils.RoundValues(data.TotalMarketCap/divisor, 0)
+			marketCap := fmt.Sprintf("%s %s %s", utils.FormatPrice(marketCapVals[0]), units, currency)
+
+			volumeVals, units := utils.RoundValues(data.TotalVolume/divisor, 0)
+			volume := fmt.Sprintf("%s %s %s", utils.FormatPrice(volumeVals[0]), units, currency)
+
+			change := fmt.Sprintf("%s %.2f", utils.UpArrow, data.MarketCapChange24h)
+			if data.MarketCapChange24h < 0 {
+				change = fmt.Sprintf("%s %.2f", utils.DownArrow, -data.MarketCapChange24h)
+			}
+
+			dominanceHistory = data.DominanceHistory
+			latest := dominanceHistory[len(dominanceHistory)-1]
+
+			page.SummaryTable.Rows = [][]string{
+				{"Total Market Cap", marketCap},
+				{"24H Volume", volume},
+				{"Market Cap Change % (24H)", change},
+				{"BTC Dominance", fmt.Sprintf("%.2f%%", latest.BTC)},
+				{"ETH Dominance", fmt.Sprintf("%.2f%%", latest.ETH)},
+				{"Active Coins", fmt.Sprintf("%d", data.ActiveCoins)},
+				{"Markets", fmt.Sprintf("%d", data.Markets)},
+			}
+			page.SummaryTable.Title = fmt.Sprintf(" Global Market (%s) ", currency)
+
+			updateDominance()
+			markStale()
+
+		case data := <-assetChannel:
+			if data.Err != nil {
+				reportError("COINS", "Coin", data.Err)
+				break
+			}
+			delete(staleSince, "COINS")
+
+			// Count advancers and decliners, and the median change, over
+			// each duration among the top coins, leaving out favourites and
+			// coins held. Coins without a change for a duration, such as
+			// coins listed recently, are left out of it.
+			rows := [][]string{}
+			for _, duration := range uw.Durations {
+				advancers, decliners := 0, 0
+				changes := []float64{}
+				for _, coin := range data.Ranked {
+					change, ok := coin.PriceChangePercentage[uw.DurationMap[duration]]
+					if !ok {
+						continue
+					}
+
+					changes = append(changes, change)
+					if change > 0 {
+						advancers++
+					} else if change < 0 {
+						decliners++
+					}
+				}
+
+				median := utils.Median(changes...)
+				change := fmt.Sprintf("%s %.2f", utils.UpArrow, median)
+				if median < 0 {
+					change = fmt.Sprintf("%s %.2f", utils.DownArrow, -median)
+				}
+
+				rows = append(rows, []string{
+					duration,
+					fmt.Sprintf("%d", advancers),
+					fmt.Sprintf("%d", decliners),
+					change,
+				})
+			}
+
+			page.BreadthTable.Rows = rows
+			page.BreadthTable.Title = fmt.Sprintf(" Market Breadth (%d coins) ", len(data.Ranked))
+			markStale()
+
+		case <-tick: // Refresh UI
+			updateUI()
+		}
+	}
+}
@@ -103,6 +103,7 @@ func (page *portfolioPage) init() {
 	page.CoinTable.ShowCursor = true
 	page.CoinTable.CursorColor = ui.ColorCyan
 	page.CoinTable.ChangeCol[3] = true
+	page.CoinTable.UniqueCol = 7
 
 	// Initialise Best Performer Table
 	page.BestPerformerTable.Title = " Best Performers "
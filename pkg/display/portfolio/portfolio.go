@@ -29,11 +29,11 @@ import (
 	"github.com/Gituser143/cryptgo/pkg/utils"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
-	"golang.org/x/sync/errgroup"
 )
 
-// DisplayPortfolio serves the prtfolio page
-func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendData *bool) error {
+// DisplayPortfolio serves the prtfolio page. The currency selected is set in
+// vsCurrency for the data to be fetched in. Coins are looked up in coinIDMap.
+func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, vsCurrency *api.Currency, coinIDMap *api.CoinIDMap, priceHub *api.PriceHub, sendData *bool) error {
 
 	// Initialise UI
 	if err := ui.Init(); err != nil {
@@ -46,14 +46,11 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 	selectedTable := page.CoinTable
 	utilitySelected := uw.None
 
-	// Variables for CoinIDs
-	coinIDMap := api.NewCoinIDMap()
-	coinIDMap.Populate()
-
 	// currency variables
 	currencyWidget := uw.NewCurrencyPage()
 	currencyID := utils.GetCurrencyID()
 	currencyID, currency, currencyVal := currencyWidget.Get(currencyID)
+	vsCurrency.Set(currencyWidget.Code(currencyID))
 
 	// get portfolio details
 	portfolioMap := utils.GetPortfolio()
@@ -69,10 +66,23 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 		utils.SaveMetadata(favourites, currencyID, portfolioMap)
 	}()
 
+	// Subscribe to live prices, keeping the latest by coin ID
+	prices := priceHub.Subscribe()
+	livePrices := map[string]float64{}
+	defer func() {
+		priceHub.Unsubscribe(prices)
+		priceHub.SetAssets("portfolio", nil)
+	}()
+
 	// Initialise help menu
 	help := widgets.NewHelpMenu()
 	help.SelectHelpMenu("PORTFOLIO")
 
+	// Initialise error page and time since which coin data is stale,
+	// which is zero while data is up to date
+	errorPage := uw.NewErrorPage()
+	staleSince := time.Time{}
+
 	// Variables for sorting CoinTable
 	coinSortIdx := -1
 	coinSortAsc := false
@@ -93,6 +103,14 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 		*sendData = !(*sendData)
 	}
 
+	// markStale marks titles of widgets showing coin data if it is stale
+	markStale := func() {
+		page.CoinTable.Title = utils.StaleTitle(page.CoinTable.Title, staleSince)
+		page.DetailsTable.Title = utils.StaleTitle(page.DetailsTable.Title, staleSince)
+		page.BestPerformerTable.Title = utils.StaleTitle(page.BestPerformerTable.Title, staleSince)
+		page.WorstPerformerTable.Title = utils.StaleTitle(page.WorstPerformerTable.Title, staleSince)
+	}
+
 	// UpdateUI to refresh UI
 	updateUI := func() {
 		// Get Terminal Dimensions
@@ -110,6 +128,9 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 		case uw.Currency:
 			currencyWidget.Resize(w, h)
 			ui.Render(currencyWidget)
+		case uw.Error:
+			errorPage.Resize(w, h)
+			ui.Render(page.Grid, errorPage)
 		default:
 			ui.Render(page.Grid)
 		}
@@ -168,6 +189,11 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 					utilitySelected = uw.Currency
 				}
 
+			case "E":
+				if utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+
 			case "e":
 				switch utilitySelected {
 				case uw.None:
@@ -178,14 +204,10 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 					if selectedTable == page.CoinTable {
 						if page.CoinTable.SelectedRow < len(page.CoinTable.Rows) {
 							row := page.CoinTable.Rows[page.CoinTable.SelectedRow]
-							symbol = row[1]
+							id, symbol = row[7], row[1]
 						}
 					}
 
-					coinIDs := coinIDMap[symbol]
-
-					id = coinIDs.CoinGeckoID
-
 					if id != "" {
 
 						inputStr := widgets.DrawEdit(uiEvents, symbol)
@@ -197,6 +219,9 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 							} else {
 								delete(portfolioMap, id)
 							}
+
+							// Save so coins outside the top coins are fetched
+							utils.SaveMetadata(favourites, currencyID, portfolioMap)
 						}
 					}
 				}
@@ -211,6 +236,7 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 						// Get currency and rate
 						currencyID = row[0]
 						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+						vsCurrency.Set(currencyWidget.Code(currencyID))
 
 						// Update currency fields
 						coinHeader[2] = fmt.Sprintf("Price (%s)", currency)
@@ -223,95 +249,30 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 					// pause UI and data send
 					pause()
 
-					symbol := ""
+					coinGeckoID := ""
 
-					// Get ID and symbol
+					// Get ID
 					if selectedTable == page.CoinTable {
 						if page.CoinTable.SelectedRow < len(page.CoinTable.Rows) {
 							row := page.CoinTable.Rows[page.CoinTable.SelectedRow]
-							symbol = row[1]
+							coinGeckoID = row[7]
 						}
 					}
 
-					coinIDs := coinIDMap[symbol]
-
-					coinCapID := coinIDs.CoinCapID
-					coinGeckoID := coinIDs.CoinGeckoID
-
 					if coinGeckoID != "" {
-						// Create new errorgroup for coin page
-						eg, coinCtx := errgroup.WithContext(ctx)
-						coinDataChannel := make(chan api.CoinData)
-						coinPriceChannel := make(chan string)
-						intervalChannel := make(chan string)
-
-						// Clear UI
-						ui.Clear()
-
-						// Serve Coin Price History
-						eg.Go(func() error {
-							err := api.GetCoinHistory(
-								coinCtx,
-								coinGeckoID,
-								intervalChannel,
-								coinDataChannel,
-							)
-							return err
-						})
-
-						// Serve Coin Asset data
-						eg.Go(func() error {
-							err := api.GetCoinDetails(coinCtx, coinGeckoID, coinDataChannel)
-							return err
-						})
-
-						// Serve favourite coin prices
-						eg.Go(func() error {
-							err := api.GetFavouritePrices(coinCtx,
-								favourites,
-								coinDataChannel,
-							)
-							return err
-						})
-
-						// Serve Live price of coin
-						if coinCapID != "" {
-							eg.Go(func() error {
-								api.GetLivePrice(coinCtx, coinCapID, coinPriceChannel)
-								// Send NA to indicate price is not being updated
-								go func() {
-									coinPriceChannel <- "NA"
-								}()
-								return nil
-							})
-						}
-
 						utils.SaveMetadata(favourites, currencyID, portfolioMap)
 
-						// Serve Visuals for coin
-						eg.Go(func() error {
-							err := coin.DisplayCoin(
-								coinCtx,
-								coinGeckoID,
-								coinIDMap,
-								intervalChannel,
-								coinDataChannel,
-								coinPriceChannel,
-								uiEvents,
-							)
+						// Serve coin page until it is closed
+						err := coin.Show(ctx, coinGeckoID, currencyWidget.Code(currencyID), coinIDMap, favourites, priceHub, uiEvents)
+						if err != nil {
+							// Unpause
+							pause()
 							return err
-						})
-
-						if err := eg.Wait(); err != nil {
-							if err.Error() != "UI Closed" {
-								// Unpause
-								pause()
-								return err
-							}
 						}
 
 						currencyID = utils.GetCurrencyID()
 						currencyID, currency, currencyVal = currencyWidget.Get(currencyID)
+						vsCurrency.Set(currencyWidget.Code(currencyID))
 
 					}
 
@@ -395,6 +356,22 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 			}
 
 		case data := <-dataChannel:
+			if data.Err != nil {
+				if staleSince.IsZero() && utilitySelected == uw.None {
+					utilitySelected = uw.Error
+				}
+				staleSince = errorPage.Fail("Coin", data.Err, staleSince)
+				markStale()
+				break
+			}
+			staleSince = time.Time{}
+
+			// Skip data priced in a currency selected earlier
+			divisor, ok := vsCurrency.Divisor(data.VsCurrency, currencyVal)
+			if !ok {
+				break
+			}
+
 			rows := [][]string{}
 
 			// Update currency headers
@@ -410,8 +387,12 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 			for _, val := range data.AllCoinData {
 				// Get coins in portfolio
 				if portfolioHolding, ok := portfolioMap[val.ID]; ok {
-					// Get coin details
-					price := fmt.Sprintf("%.2f", val.CurrentPrice/currencyVal)
+					// Get coin details, preferring the live price in USD
+					currentPrice := val.CurrentPrice / divisor
+					if p, ok := livePrices[val.ID]; ok {
+						currentPrice = p / currencyVal
+					}
+					price := utils.FormatPrice(currentPrice)
 
 					var change string
 					percentageChange := api.GetPercentageChangeForDuration(val, "24h")
@@ -424,8 +405,8 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 					rank := fmt.Sprintf("%d", val.MarketCapRank)
 					symbol := strings.ToUpper(val.Symbol)
 					holding := fmt.Sprintf("%.5f", portfolioHolding)
-					balanceFloat := val.CurrentPrice / currencyVal * portfolioHolding
-					balance := fmt.Sprintf("%.2f", balanceFloat)
+					balanceFloat := currentPrice * portfolioHolding
+					balance := utils.FormatPrice(balanceFloat)
 
 					// Aggregate data
 					rows = append(rows, []string{
@@ -436,13 +417,14 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 						holding,
 						balance,
 						"holdingPercent", // calculated after total balance is calculated
+						val.ID,
 					})
 
 					// Calculate portfolio total
 					portfolioTotal += balanceFloat
 
 					// Keep track of a coin's balance
-					balanceMap[symbol] = balanceFloat
+					balanceMap[val.ID] = balanceFloat
 
 					// Calculate best and worst performers
 					for _, duration := range durations {
@@ -471,8 +453,7 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 
 			// Update portfolio holding % values
 			for i, row := range rows {
-				symbol := row[1]
-				rows[i][6] = fmt.Sprintf("%.2f", (balanceMap[symbol]/portfolioTotal)*100)
+				rows[i][6] = fmt.Sprintf("%.2f", (balanceMap[row[7]]/portfolioTotal)*100)
 			}
 
 			// Update coin table
@@ -481,7 +462,7 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 			// Update details table
 			page.DetailsTable.Header = []string{
 				"Balance",
-				fmt.Sprintf("%.2f", portfolioTotal),
+				utils.FormatPrice(portfolioTotal),
 			}
 			page.DetailsTable.Rows = [][]string{
 				{"Currency", currency},
@@ -535,7 +516,49 @@ func DisplayPortfolio(ctx context.Context, dataChannel chan api.AssetData, sendD
 				}
 			}
 
+			markStale()
+
+		case <-prices.C:
+			updated := prices.Prices()
+			for id, p := range updated {
+				livePrices[id] = p
+			}
+
+			// Update prices and balances of coins held
+			portfolioTotal := 0.0
+			balances := make([]float64, len(page.CoinTable.Rows))
+			for i, row := range page.CoinTable.Rows {
+				balances[i], _ = strconv.ParseFloat(row[5], 64)
+
+				if p, ok := updated[row[7]]; ok {
+					balances[i] = p / currencyVal * portfolioMap[row[7]]
+					row[2] = utils.FormatPrice(p / currencyVal)
+					row[5] = utils.FormatPrice(balances[i])
+				}
+
+				portfolioTotal += balances[i]
+			}
+
+			// Update portfolio holding % values and balance
+			for i, row := range page.CoinTable.Rows {
+				row[6] = fmt.Sprintf("%.2f", (balances[i]/portfolioTotal)*100)
+			}
+			if len(page.DetailsTable.Header) == 2 {
+				page.DetailsTable.Header[1] = utils.FormatPrice(portfolioTotal)
+			}
+
+			if coinSortIdx != -1 {
+				utils.SortData(page.CoinTable.Rows, coinSortIdx, coinSortAsc, "PORTFOLIO")
+			}
+
 		case <-tick: // Refresh UI
+			// Stream live prices of coins held
+			ids := []string{}
+			for _, row := range page.CoinTable.Rows {
+				ids = append(ids, row[7])
+			}
+			priceHub.SetAssets("portfolio", ids)
+
 			updateUI()
 		}
 	}
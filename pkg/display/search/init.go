@@ -0,0 +1,74 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// searchPage holds UI items for the search dialog
+type searchPage struct {
+	ResultsTable *widgets.Table
+}
+
+// newSearchPage creates, initialises and returns a pointer to an instance of searchPage
+func newSearchPage() *searchPage {
+	page := &searchPage{
+		ResultsTable: widgets.NewTable(),
+	}
+	page.init()
+
+	return page
+}
+
+// init initialises the widgets of a searchPage
+func (page *searchPage) init() {
+	page.ResultsTable.BorderStyle.Fg = ui.ColorCyan
+	page.ResultsTable.TitleStyle.Fg = ui.ColorClear
+	page.ResultsTable.Header = []string{"Rank", "Symbol", "Name", "ID"}
+	page.ResultsTable.ColResizer = func() {
+		x := page.ResultsTable.Inner.Dx()
+		page.ResultsTable.ColWidths = []int{
+			x / 10,
+			x / 6,
+			x / 3,
+			x / 3,
+		}
+	}
+	page.ResultsTable.CursorColor = ui.ColorCyan
+	page.ResultsTable.UniqueCol = 3
+}
+
+// Resize centres the search dialog according to terminal dimensions
+func (page *searchPage) Resize(termWidth, termHeight int) {
+	textWidth := 90
+
+	textHeight := 20
+	x := (termWidth - textWidth) / 2
+	y := (termHeight - textHeight) / 2
+	if x < 0 {
+		x = 0
+		textWidth = termWidth
+	}
+	if y < 0 {
+		y = 0
+		textHeight = termHeight
+	}
+
+	page.ResultsTable.SetRect(x, y, textWidth+x, textHeight+y)
+}
@@ -0,0 +1,229 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	ui "github.com/gizak/termui/v3"
+)
+
+// Action is what is done with the coin picked in the search dialog
+type Action int
+
+const (
+	// Cancel is returned if the dialog is closed without picking a coin
+	Cancel Action = iota
+
+	// Open opens the coin page of the coin picked
+	Open
+
+	// Favourite adds the coin picked to favourites
+	Favourite
+
+	// Hold adds the coin picked to the portfolio
+	Hold
+)
+
+// debounce is how long typing must pause before a search is sent
+const debounce = 300 * time.Millisecond
+
+// result holds coins found searching for query
+type result struct {
+	query string
+	coins []api.SearchResult
+	err   error
+}
+
+// DisplaySearch shows a dialog searching all coins known to the provider as
+// a query is typed, and returns the coin picked with what to do with it.
+// Searches are sent once typing pauses. An error is only returned if the
+// context is cancelled.
+func DisplaySearch(ctx context.Context, uiEvents <-chan ui.Event) (api.SearchResult, Action, error) {
+	page := newSearchPage()
+	defer ui.Clear()
+
+	// Cancel searches still running once closed
+	searchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	resultChannel := make(chan result)
+
+	// The query is edited while typing, otherwise keys act on results
+	query := []rune{}
+	typing := true
+	coins := []api.SearchResult{}
+	status := "type a name or symbol"
+
+	// debounced fires once typing has paused, it is nil while no search
+	// is due
+	var debounced <-chan time.Time
+
+	// updateUI to refresh UI
+	updateUI := func() {
+		cursor, keys := "_", "<Tab> results"
+		if !typing {
+			cursor, keys = "", "<Enter> open, s star, e portfolio, / edit"
+		}
+		page.ResultsTable.Title = fmt.Sprintf(" Search: %s%s (%s) - %s ", string(query), cursor, status, keys)
+		page.ResultsTable.ShowCursor = !typing || len(coins) > 0
+
+		rows := [][]string{}
+		for _, coin := range coins {
+			rank := "-"
+			if coin.MarketCapRank > 0 {
+				rank = fmt.Sprintf("%d", coin.MarketCapRank)
+			}
+
+			rows = append(rows, []string{
+				rank,
+				strings.ToUpper(coin.Symbol),
+				coin.Name,
+				coin.ID,
+			})
+		}
+		page.ResultsTable.Rows = rows
+
+		w, h := ui.TerminalDimensions()
+		ui.Clear()
+		page.Resize(w, h)
+		ui.Render(page.ResultsTable)
+	}
+
+	// selected returns the coin under the cursor
+	selected := func() (api.SearchResult, bool) {
+		if page.ResultsTable.SelectedRow < len(coins) {
+			return coins[page.ResultsTable.SelectedRow], true
+		}
+
+		return api.SearchResult{}, false
+	}
+
+	updateUI()
+
+	for {
+		select {
+		case <-ctx.Done(): // Context cancelled, exit
+			return api.SearchResult{}, Cancel, ctx.Err()
+
+		case e := <-uiEvents: // keyboard events
+			switch e.ID {
+			case "<C-c>", "<Escape>":
+				return api.SearchResult{}, Cancel, nil
+
+			case "<Resize>":
+				// Redrawn below
+
+			case "<Enter>":
+				if coin, ok := selected(); ok {
+					return coin, Open, nil
+				}
+
+			case "<Tab>":
+				typing = !typing || len(coins) == 0
+
+			case "<Down>":
+				page.ResultsTable.ScrollDown()
+
+			case "<Up>":
+				page.ResultsTable.ScrollUp()
+
+			default:
+				if !typing {
+					switch e.ID {
+					case "j":
+						page.ResultsTable.ScrollDown()
+					case "k":
+						page.ResultsTable.ScrollUp()
+					case "g", "<Home>":
+						page.ResultsTable.ScrollTop()
+					case "G", "<End>":
+						page.ResultsTable.ScrollBottom()
+					case "/":
+						typing = true
+					case "s":
+						if coin, ok := selected(); ok {
+							return coin, Favourite, nil
+						}
+					case "e":
+						if coin, ok := selected(); ok {
+							return coin, Hold, nil
+						}
+					}
+					break
+				}
+
+				// Edit query, searching once typing pauses
+				switch e.ID {
+				case "<Backspace>", "<C-<Backspace>>":
+					if len(query) > 0 {
+						query = query[:len(query)-1]
+					}
+				case "<Space>":
+					query = append(query, ' ')
+				default:
+					if len([]rune(e.ID)) != 1 {
+						continue
+					}
+					query = append(query, []rune(e.ID)[0])
+				}
+				status = "searching"
+				debounced = time.After(debounce)
+			}
+
+			updateUI()
+
+		case <-debounced: // Typing paused, search
+			debounced = nil
+			q := string(query)
+			if strings.TrimSpace(q) == "" {
+				coins = []api.SearchResult{}
+				status = "type a name or symbol"
+				updateUI()
+				break
+			}
+
+			go func() {
+				found, err := api.SearchCoins(ctx, q)
+				select {
+				case <-searchCtx.Done():
+				case resultChannel <- result{query: q, coins: found, err: err}:
+				}
+			}()
+
+		case r := <-resultChannel: // Results of a search
+			// Skip results of queries edited since
+			if r.query != string(query) || debounced != nil {
+				break
+			}
+
+			if r.err != nil {
+				status = fmt.Sprintf("error: %v", r.err)
+			} else {
+				coins = r.coins
+				status = fmt.Sprintf("%d coins", len(coins))
+			}
+			page.ResultsTable.SelectedItem = ""
+			page.ResultsTable.ScrollTop()
+
+			updateUI()
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// ActivityTable holds a table showing community and developer activity of a
+// coin, with the trend of each statistic since a day or more earlier
+type ActivityTable struct {
+	*widgets.Table
+}
+
+// NewActivityPage creates, initialises and returns a pointer to an instance of ActivityTable
+func NewActivityPage() *ActivityTable {
+	a := &ActivityTable{
+		Table: widgets.NewTable(),
+	}
+
+	a.Table.Title = " Activity "
+	a.Table.Header = []string{"Statistic", "Value", "Trend"}
+	a.Table.ColWidths = []int{5, 5, 5}
+	a.Table.ColResizer = func() {
+		x := a.Table.Inner.Dx()
+		a.Table.ColWidths = []int{
+			x / 2,
+			x / 4,
+			x / 4,
+		}
+	}
+	a.Table.Rows = [][]string{{"Fetching activity..."}}
+	return a
+}
+
+// Resize helps resize the ActivityTable according to terminal dimensions
+func (a *ActivityTable) Resize(termWidth, termHeight int) {
+	textWidth := 60
+
+	textHeight := len(a.Table.Rows) + 3
+	x := (termWidth - textWidth) / 2
+	y := (termHeight - textHeight) / 2
+	if x < 0 {
+		x = 0
+		textWidth = termWidth
+	}
+	if y < 0 {
+		y = 0
+		textHeight = termHeight
+	}
+
+	a.Table.SetRect(x, y, textWidth+x, textHeight+y)
+}
+
+// Draw puts the required text into the widget
+func (a *ActivityTable) Draw(buf *ui.Buffer) {
+	a.Table.Draw(buf)
+}
+
+// Update sets activity as rows of the table. A statistic which changed since
+// the activity in since, recorded a day or more earlier, shows the change
+// with an arrow. No trends are shown if since has a zero Time.
+func (a *ActivityTable) Update(activity api.Activity, since api.ActivitySample) {
+	stats := []struct {
+		name           string
+		value, prevVal int
+	}{
+		{"GitHub Stars", activity.GitHubStars, -1},
+		{"Commits (4 weeks)", activity.Commits4Weeks, -1},
+		{"Open Issues", activity.OpenIssues, -1},
+		{"Reddit Subscribers", activity.RedditSubscribers, -1},
+		{"Twitter Followers", activity.TwitterFollowers, -1},
+	}
+
+	a.Table.Title = " Activity "
+	if !since.Time.IsZero() {
+		a.Table.Title = fmt.Sprintf(" Activity (trend since %s) ", utils.FormatDate(since.Time))
+
+		previous := since.Activity
+		stats[0].prevVal = previous.GitHubStars
+		stats[1].prevVal = previous.Commits4Weeks
+		stats[2].prevVal = previous.OpenIssues
+		stats[3].prevVal = previous.RedditSubscribers
+		stats[4].prevVal = previous.TwitterFollowers
+	}
+
+	rows := [][]string{}
+	for _, stat := range stats {
+		if stat.value < 0 {
+			rows = append(rows, []string{stat.name, "NA", ""})
+			continue
+		}
+
+		trend := "-"
+		if stat.prevVal >= 0 && stat.value != stat.prevVal {
+			diff := stat.value - stat.prevVal
+			if diff > 0 {
+				trend = fmt.Sprintf("%s %d", utils.UpArrow, diff)
+			} else {
+				trend = fmt.Sprintf("%s %d", utils.DownArrow, -diff)
+			}
+		}
+
+		rows = append(rows, []string{stat.name, fmt.Sprintf("%d", stat.value), trend})
+	}
+
+	a.Table.Rows = rows
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// CategoriesTable holds a table to pick the category of coins shown
+type CategoriesTable struct {
+	*widgets.Table
+}
+
+// NewCategoriesPage creates, initialises and returns a pointer to an instance of CategoriesTable
+func NewCategoriesPage() *CategoriesTable {
+	c := &CategoriesTable{
+		Table: widgets.NewTable(),
+	}
+
+	c.Table.Title = " Categories: Loading "
+	c.Table.Header = []string{"Category", "Market Cap", "Change 24h %", "Volume 24h"}
+	c.Table.CursorColor = ui.ColorCyan
+	c.Table.ShowCursor = true
+	c.Table.UniqueCol = 4
+	c.Table.ChangeCol[2] = true
+	c.Table.ColWidths = []int{5, 5, 5, 5}
+	c.Table.ColResizer = func() {
+		x := c.Table.Inner.Dx()
+		c.Table.ColWidths = []int{
+			2 * x / 5,
+			x / 5,
+			x / 6,
+			x / 5,
+		}
+	}
+	return c
+}
+
+// Resize helps resize the CategoriesTable according to terminal dimensions
+func (c *CategoriesTable) Resize(termWidth, termHeight int) {
+	textWidth := 100
+
+	textHeight := len(c.Table.Rows) + 3
+	if textHeight < 6 {
+		textHeight = 6
+	}
+	x := (termWidth - textWidth) / 2
+	y := (termHeight - textHeight) / 2
+	if x < 0 {
+		x = 0
+		textWidth = termWidth
+	}
+	if y < 0 {
+		y = 0
+		textHeight = termHeight
+	}
+
+	c.Table.SetRect(x, y, textWidth+x, textHeight+y)
+}
+
+// Draw puts the required text into the widget
+func (c *CategoriesTable) Draw(buf *ui.Buffer) {
+	c.Table.Draw(buf)
+}
+
+// UpdateRows lists categories after a row selecting all coins, with market
+// caps and volumes in USD divided by divisor to show them in currency
+func (c *CategoriesTable) UpdateRows(categories []api.CategoryMarket, currency string, divisor float64) {
+	// formatAmount formats a market cap or volume in the selected currency,
+	// which categories may lack
+	formatAmount := func(amount float64) string {
+		if amount <= 0 {
+			return "NA"
+		}
+		return utils.FormatAmount(amount / divisor)
+	}
+
+	rows := [][]string{{"All coins", "", "", "", ""}}
+	for _, category := range categories {
+		change := fmt.Sprintf("%s %.2f", utils.UpArrow, category.MarketCapChange24h)
+		if category.MarketCapChange24h < 0 {
+			change = fmt.Sprintf("%s %.2f", utils.DownArrow, -category.MarketCapChange24h)
+		}
+
+		rows = append(rows, []string{
+			category.Name,
+			formatAmount(category.MarketCap),
+			change,
+			formatAmount(category.Volume24h),
+			category.ID, // not displayed, identifies the category
+		})
+	}
+
+	c.Table.Header[1] = fmt.Sprintf("Market Cap (%s)", currency)
+	c.Table.Header[3] = fmt.Sprintf("Volume 24h (%s)", currency)
+	c.Table.Rows = rows
+	c.Table.Title = fmt.Sprintf(" Categories: %d ", len(categories))
+}
+
+// Selected returns the ID and name of the category under the cursor, the ID
+// is empty if all coins are selected
+func (c *CategoriesTable) Selected() (string, string, bool) {
+	if c.Table.SelectedRow < len(c.Table.Rows) {
+		row := c.Table.Rows[c.Table.SelectedRow]
+		return row[4], row[0], true
+	}
+
+	return "", "", false
+}
+
+// SetError shows an error met fetching categories in the title
+func (c *CategoriesTable) SetError(err error) {
+	c.Table.Title = fmt.Sprintf(" Categories: %s ", err)
+}
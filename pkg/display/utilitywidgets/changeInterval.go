@@ -21,7 +21,7 @@ import (
 	ui "github.com/gizak/termui/v3"
 )
 
-var intervalRows = [][]string{{"24 Hours"}, {"7 Days"}, {"14 Days"}, {"30 Days"}, {"90 Days"}, {"180 Days"}, {"1 Year"}, {"5 Years"}}
+var intervalRows = [][]string{{"24 Hours"}, {"7 Days"}, {"14 Days"}, {"30 Days"}, {"90 Days"}, {"180 Days"}, {"1 Year"}, {"5 Years"}, {"Max"}}
 
 // IntervalMap maps given interval string to format required by CoinGecko API
 var IntervalMap = map[string]string{
@@ -33,6 +33,7 @@ var IntervalMap = map[string]string{
 	"180 Days": "180d",
 	"1 Year":   "1yr",
 	"5 Years":  "5yr",
+	"Max":      "max",
 }
 
 // ChangeIntervalDurationTable holds a table to help user change duration intervals
@@ -21,7 +21,8 @@ import (
 	ui "github.com/gizak/termui/v3"
 )
 
-var durationRows = [][]string{{"1 Hour"}, {"24 Hours"}, {"7 Days"}, {"14 Days"}, {"30 Days"}, {"200 Days"}, {"1 Year"}}
+// Durations lists the keys of DurationMap, shortest first
+var Durations = []string{"1 Hour", "24 Hours", "7 Days", "14 Days", "30 Days", "200 Days", "1 Year"}
 
 // DurationMap maps duration strings to the format required by coinGecko API
 var DurationMap = map[string]string{
@@ -48,7 +49,9 @@ func NewChangePercentPage() *ChangePercentageDurationTable {
 
 	c.Table.Title = " Select Duration for Percentage Change "
 	c.Table.Header = []string{"Duration"}
-	c.Table.Rows = durationRows
+	for _, duration := range Durations {
+		c.Table.Rows = append(c.Table.Rows, []string{duration})
+	}
 	c.Table.CursorColor = ui.ColorCyan
 	c.Table.ShowCursor = true
 	c.Table.ColWidths = []int{5}
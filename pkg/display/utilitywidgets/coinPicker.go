@@ -0,0 +1,121 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// CoinPicker holds a table to pick one of many coins sharing a symbol
+type CoinPicker struct {
+	*widgets.Table
+	Coins []api.CoinID
+}
+
+// NewCoinPicker creates, initialises and returns a pointer to an instance of
+// CoinPicker
+func NewCoinPicker() *CoinPicker {
+	c := &CoinPicker{
+		Table: widgets.NewTable(),
+	}
+
+	c.Table.Header = []string{"Name", "Symbol", "Rank", "Market Cap (USD)"}
+	c.Table.CursorColor = ui.ColorCyan
+	c.Table.ShowCursor = true
+	c.Table.UniqueCol = 4
+	c.Table.ColResizer = func() {
+		x := c.Table.Inner.Dx()
+		c.Table.ColWidths = []int{
+			4 * x / 10,
+			2 * x / 10,
+			x / 10,
+			3 * x / 10,
+		}
+	}
+
+	return c
+}
+
+// Set shows coins to pick from
+func (c *CoinPicker) Set(coins []api.CoinID) {
+	c.Coins = coins
+	c.Table.Title = fmt.Sprintf(" Pick %s ", coins[0].Symbol)
+
+	rows := [][]string{}
+	for _, coin := range coins {
+		rank := "-"
+		if coin.Rank > 0 {
+			rank = fmt.Sprintf("%d", coin.Rank)
+		}
+
+		marketCap := "-"
+		if coin.MarketCap > 0 {
+			vals, units := utils.RoundValues(coin.MarketCap, 0)
+			marketCap = fmt.Sprintf("%.2f %s", vals[0], units)
+		}
+
+		rows = append(rows, []string{
+			coin.Name,
+			coin.Symbol,
+			rank,
+			marketCap,
+			coin.CoinGeckoID, // not displayed, identifies the coin
+		})
+	}
+
+	c.Table.Rows = rows
+	c.Table.SelectedItem = ""
+	c.Table.ScrollTop()
+}
+
+// Selected returns the coin under the cursor
+func (c *CoinPicker) Selected() (api.CoinID, bool) {
+	if c.Table.SelectedRow < len(c.Coins) {
+		return c.Coins[c.Table.SelectedRow], true
+	}
+
+	return api.CoinID{}, false
+}
+
+// Resize helps resize the CoinPicker according to terminal dimensions
+func (c *CoinPicker) Resize(termWidth, termHeight int) {
+	textWidth := 80
+
+	textHeight := len(c.Table.Rows) + 3
+	x := (termWidth - textWidth) / 2
+	y := (termHeight - textHeight) / 2
+	if x < 0 {
+		x = 0
+		textWidth = termWidth
+	}
+	if y < 0 {
+		y = 0
+		textHeight = termHeight
+	}
+
+	c.Table.SetRect(x, y, textWidth+x, textHeight+y)
+}
+
+// Draw puts the required text into the widget
+func (c *CoinPicker) Draw(buf *ui.Buffer) {
+	c.Table.Draw(buf)
+}
@@ -17,11 +17,12 @@ limitations under the License.
 package utilitywidgets
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strconv"
+	"strings"
+	"sync"
 
+	"github.com/Gituser143/cryptgo/pkg/api"
 	"github.com/Gituser143/cryptgo/pkg/utils"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
@@ -42,14 +43,23 @@ type AllCurrencyData struct {
 	Timestamp uint             `json:"timestamp"`
 }
 
-// CurrencyTable is a widget used to display currencyies, symbols and rates
+// CurrencyTable is a widget used to display currencyies, symbols and rates.
+// Rates are read from the cache and refreshed in the background once expired.
 type CurrencyTable struct {
 	*widgets.Table
 	IDMap *CurrencyIDMap
+
+	mutex      sync.RWMutex // guards IDMap and refreshing
+	refreshing bool
+
+	// loaded is closed once rates are first loaded or fail to be fetched
+	loaded     chan struct{}
+	loadedOnce sync.Once
 }
 
 // Currency holds information of a single currency, it used to populate currencyIDMaps
 type CurrencyValue struct {
+	Code    string
 	Symbol  string
 	RateUSD float64
 	Type    string
@@ -64,71 +74,68 @@ func NewCurrencyIDMap() CurrencyIDMap {
 	return c
 }
 
-// Populate fetches currency rates and populates the map
-func (c CurrencyIDMap) Populate() {
-	url := "https://api.coincap.io/v2/rates"
-	method := "GET"
-
-	client := &http.Client{}
-
-	// Create Request
-	req, err := http.NewRequest(method, url, nil)
-	if err != nil {
-		return
-	}
-
-	// Send Request and get response
-	res, err := client.Do(req)
-	if err != nil {
-		res.Body.Close()
-		return
-	}
-
-	data := utils.AllCurrencyData{}
-
-	// Read response
-	err = json.NewDecoder(res.Body).Decode(&data)
-	res.Body.Close()
-	if err != nil {
-		return
-	}
-
+// Populate populates the map with the given currency rates
+func (c CurrencyIDMap) Populate(rates []api.CurrencyRate) {
 	// Iterate over currencies
-	for _, curr := range data.Data {
-		currencyID := curr.ID
-		rate, err := strconv.ParseFloat(curr.RateUSD, 64)
-		if err == nil {
-
-			c[currencyID] = CurrencyValue{
-				Symbol:  fmt.Sprintf("%s %s", curr.Symbol, curr.CurrencySymbol),
-				RateUSD: rate,
-				Type:    curr.Type,
-			}
+	for _, curr := range rates {
+		c[curr.ID] = CurrencyValue{
+			Code:    curr.Symbol,
+			Symbol:  strings.TrimSpace(fmt.Sprintf("%s %s", curr.Symbol, curr.CurrencySymbol)),
+			RateUSD: curr.RateUSD,
+			Type:    curr.Type,
 		}
 	}
 }
 
 // Get returns the symbol and USD rate for a given currency ID
 // If the given currency ID does not exist in the Map, values
-// for US Dollar are returned
+// for US Dollar are returned. When no rates are cached, it waits for them to
+// be fetched first. If they cannot be, the currency ID is kept, so the
+// selected currency is not lost.
 func (c *CurrencyTable) Get(currencyID string) (string, string, float64) {
+	<-c.loaded
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
 	if val, ok := (*c.IDMap)[currencyID]; ok {
 		return currencyID, val.Symbol, val.RateUSD
 	}
 
+	if len(*c.IDMap) == 0 {
+		return currencyID, "USD $", 1
+	}
+
 	return "united-states-dollar", "USD $", 1
 
 }
 
+// Code returns the code, such as "EUR", of the currency with the given ID.
+// If the currency ID does not exist in the Map, "USD" is returned. Like Get,
+// it waits for rates to be fetched when none are cached.
+func (c *CurrencyTable) Code(currencyID string) string {
+	<-c.loaded
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if val, ok := (*c.IDMap)[currencyID]; ok {
+		return val.Code
+	}
+
+	return "USD"
+}
+
 // NewCurrencyPage creates, initialises and returns a pointer to an instance of CurrencyTable
 func NewCurrencyPage() *CurrencyTable {
 	idMap := NewCurrencyIDMap()
-	idMap.Populate()
 
 	c := &CurrencyTable{
-		Table: widgets.NewTable(),
-		IDMap: &idMap,
+		Table:  widgets.NewTable(),
+		IDMap:  &idMap,
+		loaded: make(chan struct{}),
 	}
+	c.load()
 
 	c.Table.Title = " Select Currency "
 	c.Table.Header = []string{"Currency", "Symbol", "Type", "USD rate"}
@@ -148,6 +155,61 @@ func NewCurrencyPage() *CurrencyTable {
 	return c
 }
 
+// load populates IDMap from the cache at once, even if the rates cached have
+// expired, and refreshes them in the background if so. When none are cached
+// they are fetched in the background too, and Get waits for them.
+func (c *CurrencyTable) load() {
+	rates, expired, ok := api.CachedCurrencyRates()
+	if !ok {
+		go c.refresh()
+		return
+	}
+
+	idMap := NewCurrencyIDMap()
+	idMap.Populate(rates)
+
+	c.mutex.Lock()
+	c.IDMap = &idMap
+	c.mutex.Unlock()
+	c.markLoaded()
+
+	if expired {
+		go c.refresh()
+	}
+}
+
+// refresh fetches currency rates and replaces IDMap with them, current rates
+// are kept if they cannot be fetched
+func (c *CurrencyTable) refresh() {
+	c.mutex.Lock()
+	if c.refreshing {
+		c.mutex.Unlock()
+		return
+	}
+	c.refreshing = true
+	c.mutex.Unlock()
+
+	rates, err := api.FetchCurrencyRates(context.Background())
+
+	c.mutex.Lock()
+	c.refreshing = false
+	if err == nil {
+		idMap := NewCurrencyIDMap()
+		idMap.Populate(rates)
+		c.IDMap = &idMap
+	}
+	c.mutex.Unlock()
+	c.markLoaded()
+}
+
+// markLoaded lets Get and Code return once rates are loaded, or could not be
+// fetched
+func (c *CurrencyTable) markLoaded() {
+	c.loadedOnce.Do(func() {
+		close(c.loaded)
+	})
+}
+
 // Resize resizes the Currency Table as per given terminal dimensions
 func (c *CurrencyTable) Resize(termWidth, termHeight int) {
 	textWidth := 80
@@ -177,7 +239,8 @@ func (c *CurrencyTable) Draw(buf *ui.Buffer) {
 	c.Table.Draw(buf)
 }
 
-// UpdateRows fetches rates of all currencies and updates them as rows in the table
+// UpdateRows updates rates of all currencies as rows in the table, with the
+// last rates known
 func (c *CurrencyTable) UpdateRows(allCurrencies bool) {
 	currencies := map[string]bool{
 		"united-states-dollar":   true,
@@ -188,9 +251,16 @@ func (c *CurrencyTable) UpdateRows(allCurrencies bool) {
 		"australian-dollar":      true,
 		"canadian-dollar":        true,
 		"chinese-yuan-renminbi":  true,
+		"bitcoin":                true,
+		"satoshi":                true,
+		"ethereum":               true,
+		"gwei":                   true,
 	}
 
-	c.IDMap.Populate()
+	c.load()
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 
 	rows := make([][]string, 0)
 
@@ -202,7 +272,7 @@ func (c *CurrencyTable) UpdateRows(allCurrencies bool) {
 				currencyID,
 				currency.Symbol,
 				currency.Type,
-				fmt.Sprintf("%.4f", currency.RateUSD),
+				utils.FormatPrice(currency.RateUSD),
 			}
 
 			rows = append(rows, row)
@@ -216,7 +286,7 @@ func (c *CurrencyTable) UpdateRows(allCurrencies bool) {
 				currencyID,
 				currency.Symbol,
 				currency.Type,
-				fmt.Sprintf("%.4f", currency.RateUSD),
+				utils.FormatPrice(currency.RateUSD),
 			}
 
 			rows = append(rows, row)
@@ -0,0 +1,164 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// ErrorPage holds a table showing the last error met while fetching data
+type ErrorPage struct {
+	*widgets.Table
+	Source     string
+	Err        error
+	StaleSince time.Time
+	LastFailed time.Time
+}
+
+// NewErrorPage creates, initialises and returns a pointer to an instance of
+// ErrorPage
+func NewErrorPage() *ErrorPage {
+	e := &ErrorPage{
+		Table: widgets.NewTable(),
+	}
+
+	e.Table.Title = " Error "
+	e.Table.BorderStyle.Fg = ui.ColorRed
+	e.Table.TitleStyle.Fg = ui.ColorClear
+	e.Table.ColResizer = func() {
+		x := e.Table.Inner.Dx()
+		e.Table.ColWidths = []int{x}
+	}
+
+	return e
+}
+
+// Update records err as the last error met while fetching data for source,
+// which has not been refreshed since staleSince
+func (e *ErrorPage) Update(source string, err error, staleSince time.Time) {
+	e.Source = source
+	e.Err = err
+	e.StaleSince = staleSince
+	e.LastFailed = time.Now()
+}
+
+// Fail records err as the last error met while fetching data for source and
+// returns the time since which that data is stale: staleSince if it is set,
+// now otherwise. Pages keep showing the last data received until it is
+// refreshed, with titles marked stale.
+func (e *ErrorPage) Fail(source string, err error, staleSince time.Time) time.Time {
+	if staleSince.IsZero() {
+		staleSince = time.Now()
+	}
+	e.Update(source, err, staleSince)
+
+	return staleSince
+}
+
+// Resize helps resize the ErrorPage according to terminal dimensions
+func (e *ErrorPage) Resize(termWidth, termHeight int) {
+	textWidth := 60
+	if textWidth > termWidth {
+		textWidth = termWidth
+	}
+
+	e.updateRows(textWidth - 3)
+
+	textHeight := len(e.Table.Rows) + 3
+	x := (termWidth - textWidth) / 2
+	y := (termHeight - textHeight) / 2
+	if y < 0 {
+		y = 0
+		textHeight = termHeight
+	}
+
+	e.Table.SetRect(x, y, textWidth+x, textHeight+y)
+}
+
+// updateRows sets the rows of the table, wrapping the error message to width
+func (e *ErrorPage) updateRows(width int) {
+	if e.Err == nil {
+		e.Table.Rows = [][]string{
+			{"No errors"},
+			{""},
+			{"To close this prompt: <Esc>"},
+		}
+		return
+	}
+
+	rows := [][]string{
+		{fmt.Sprintf("%s data stale since %s", e.Source, e.StaleSince.Format("15:04:05"))},
+		{fmt.Sprintf("Last failed at %s, retrying in the background", e.LastFailed.Format("15:04:05"))},
+		{""},
+	}
+
+	for _, line := range wrap(e.Err.Error(), width) {
+		rows = append(rows, []string{line})
+	}
+
+	rows = append(rows, []string{""}, []string{"To close this prompt: <Esc>"})
+
+	e.Table.Rows = rows
+}
+
+// wrap splits text into lines of at most width characters, breaking lines
+// between words where possible
+func wrap(text string, width int) []string {
+	if width < 1 {
+		return []string{text}
+	}
+
+	lines := []string{}
+	line := []rune{}
+	for _, word := range strings.Fields(text) {
+		w := []rune(word)
+
+		// Start a new line if the word does not fit on the current one
+		if len(line) > 0 && len(line)+1+len(w) > width {
+			lines = append(lines, string(line))
+			line = []rune{}
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+
+		// Split words longer than a line
+		for len(line)+len(w) > width {
+			n := width - len(line)
+			lines = append(lines, string(append(line, w[:n]...)))
+			line = []rune{}
+			w = w[n:]
+		}
+		line = append(line, w...)
+	}
+
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+
+	return lines
+}
+
+// Draw puts the required text into the widget
+func (e *ErrorPage) Draw(buf *ui.Buffer) {
+	e.Table.Draw(buf)
+}
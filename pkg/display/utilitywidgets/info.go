@@ -0,0 +1,164 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// InfoPage holds a table showing links about a coin, contract addresses and
+// its description. Each row with a link or address keeps it in a hidden
+// column, to be opened or copied.
+type InfoPage struct {
+	*widgets.Table
+	details api.CoinDetails
+	width   int
+}
+
+// NewInfoPage creates, initialises and returns a pointer to an instance of InfoPage
+func NewInfoPage() *InfoPage {
+	i := &InfoPage{
+		Table: widgets.NewTable(),
+	}
+
+	i.Table.Title = " Info "
+	i.Table.Header = []string{"", ""}
+	i.Table.CursorColor = ui.ColorCyan
+	i.Table.ShowCursor = true
+	i.Table.ColWidths = []int{5, 5}
+	i.Table.ColResizer = func() {
+		x := i.Table.Inner.Dx()
+		i.Table.ColWidths = []int{
+			x / 4,
+			x - x/4,
+		}
+	}
+	i.Table.Rows = [][]string{{"Fetching info..."}}
+	return i
+}
+
+// Update sets the details shown in the table
+func (i *InfoPage) Update(details api.CoinDetails) {
+	i.details = details
+	i.Table.Header = []string{"Name", details.Name}
+	i.Table.Title = " Info "
+	i.updateRows()
+}
+
+// Resize helps resize the InfoPage according to terminal dimensions
+func (i *InfoPage) Resize(termWidth, termHeight int) {
+	textWidth := 100
+	if textWidth > termWidth {
+		textWidth = termWidth
+	}
+
+	// Wrap the description to the value column
+	i.width = (textWidth-2)*3/4 - 1
+	i.updateRows()
+
+	textHeight := len(i.Table.Rows) + 3
+	x := (termWidth - textWidth) / 2
+	y := (termHeight - textHeight) / 2
+	if y < 0 {
+		y = 0
+		textHeight = termHeight
+	}
+
+	i.Table.SetRect(x, y, textWidth+x, textHeight+y)
+}
+
+// Draw puts the required text into the widget
+func (i *InfoPage) Draw(buf *ui.Buffer) {
+	i.Table.Draw(buf)
+}
+
+// Selected returns the link or address in the selected row, empty if the
+// row has none
+func (i *InfoPage) Selected() string {
+	if i.SelectedRow < len(i.Rows) && len(i.Rows[i.SelectedRow]) > 2 {
+		return i.Rows[i.SelectedRow][2]
+	}
+	return ""
+}
+
+// SetStatus shows the outcome of an action on the selected row in the title
+func (i *InfoPage) SetStatus(status string) {
+	i.Table.Title = fmt.Sprintf(" Info: %s ", status)
+}
+
+// updateRows sets the rows of the table, wrapping the description
+func (i *InfoPage) updateRows() {
+	if i.details.Name == "" {
+		return
+	}
+
+	rows := [][]string{}
+	addLink := func(name, link string) {
+		if link != "" {
+			rows = append(rows, []string{name, link, link})
+		}
+	}
+
+	addLink("Homepage", i.details.Website)
+	addLink("Whitepaper", i.details.Whitepaper)
+	for _, repo := range i.details.Repos {
+		addLink("GitHub", repo)
+	}
+	addLink("Subreddit", i.details.Subreddit)
+
+	platforms := []string{}
+	for platform := range i.details.Contracts {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	for _, platform := range platforms {
+		addLink(fmt.Sprintf("Contract (%s)", platform), i.details.Contracts[platform])
+	}
+
+	if i.details.Description != "" {
+		if len(rows) > 0 {
+			rows = append(rows, []string{"", ""})
+		}
+
+		// Keep paragraphs of the description apart
+		label := "Description"
+		for _, paragraph := range strings.Split(i.details.Description, "\n") {
+			if strings.TrimSpace(paragraph) == "" {
+				continue
+			}
+			if label == "" {
+				rows = append(rows, []string{"", ""})
+			}
+			for _, line := range wrap(paragraph, i.width) {
+				rows = append(rows, []string{label, line})
+				label = ""
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"No info available", ""})
+	}
+
+	i.Table.Rows = rows
+}
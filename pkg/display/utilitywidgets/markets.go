@@ -0,0 +1,159 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// trustLevels maps trust scores of tickers to the level shown
+var trustLevels = map[string]string{
+	"green":  "High",
+	"yellow": "Medium",
+	"red":    "Low",
+}
+
+// MarketsTable holds a table listing the exchanges and pairs a coin trades in
+type MarketsTable struct {
+	*widgets.Table
+	SortIdx int
+	SortAsc bool
+	header  []string
+}
+
+// NewMarketsPage creates, initialises and returns a pointer to an instance of MarketsTable
+func NewMarketsPage() *MarketsTable {
+	m := &MarketsTable{
+		Table:   widgets.NewTable(),
+		SortIdx: 3,
+		SortAsc: false,
+	}
+
+	m.header = []string{"Exchange", "Pair", "Last", "Volume", "Spread %", "Dev %", "Trust"}
+	m.Table.Title = " Markets "
+	m.Table.Header = append([]string{}, m.header...)
+	m.Table.CursorColor = ui.ColorCyan
+	m.Table.ShowCursor = true
+	m.Table.ColWidths = []int{5, 5, 5, 5, 5, 5, 5}
+	m.Table.ColResizer = func() {
+		x := m.Table.Inner.Dx()
+		m.Table.ColWidths = []int{
+			x / 5,
+			x / 6,
+			x / 7,
+			x / 6,
+			x / 10,
+			x / 10,
+			x / 10,
+		}
+	}
+	return m
+}
+
+// Resize helps resize the MarketsTable according to terminal dimensions
+func (m *MarketsTable) Resize(termWidth, termHeight int) {
+	textWidth := 120
+
+	textHeight := len(m.Table.Rows) + 3
+	x := (termWidth - textWidth) / 2
+	y := (termHeight - textHeight) / 2
+	if x < 0 {
+		x = 0
+		textWidth = termWidth
+	}
+	if y < 0 {
+		y = 0
+		textHeight = termHeight
+	}
+
+	m.Table.SetRect(x, y, textWidth+x, textHeight+y)
+}
+
+// Draw puts the required text into the widget
+func (m *MarketsTable) Draw(buf *ui.Buffer) {
+	m.Table.Draw(buf)
+}
+
+// UpdateRows sets tickers as rows of the table, with prices and volumes in
+// USD divided by divisor to show them in currency. Dev % is the deviation of
+// the last price from the median over tickers which are neither stale nor
+// anomalous, flagging venues out of line with the rest of the market.
+func (m *MarketsTable) UpdateRows(tickers []api.Ticker, currency string, divisor float64) {
+	prices := []float64{}
+	for _, t := range tickers {
+		if !t.IsStale && !t.IsAnomaly && t.LastUSD > 0 {
+			prices = append(prices, t.LastUSD)
+		}
+	}
+	median := utils.Median(prices...)
+
+	rows := make([][]string, 0, len(tickers))
+	for _, t := range tickers {
+		deviation := "NA"
+		if median > 0 && t.LastUSD > 0 {
+			deviation = fmt.Sprintf("%+.2f", (t.LastUSD-median)/median*100)
+		}
+
+		trust, ok := trustLevels[t.TrustScore]
+		if !ok {
+			trust = "NA"
+		}
+		if t.IsAnomaly {
+			trust += " (anomaly)"
+		} else if t.IsStale {
+			trust += " (stale)"
+		}
+
+		rows = append(rows, []string{
+			t.Exchange,
+			fmt.Sprintf("%s/%s", t.Base, t.Target),
+			utils.FormatPrice(t.LastUSD / divisor),
+			utils.FormatPrice(t.VolumeUSD / divisor),
+			fmt.Sprintf("%.2f", t.Spread),
+			deviation,
+			trust,
+		})
+	}
+
+	m.header[2] = fmt.Sprintf("Last (%s)", currency)
+	m.header[3] = fmt.Sprintf("Volume (%s)", currency)
+	m.Rows = rows
+	m.Title = fmt.Sprintf(" Markets: %d pairs, median %s %s ", len(tickers), utils.FormatPrice(median/divisor), currency)
+	m.Sort(m.SortIdx, m.SortAsc)
+}
+
+// Sort sorts rows on the column at idx and marks the column in the header
+func (m *MarketsTable) Sort(idx int, asc bool) {
+	if idx < 0 || idx >= len(m.header) {
+		return
+	}
+
+	m.SortIdx, m.SortAsc = idx, asc
+	utils.SortData(m.Rows, idx, asc, "MARKETS")
+
+	arrow := utils.DownArrow
+	if asc {
+		arrow = utils.UpArrow
+	}
+	m.Header = append([]string{}, m.header...)
+	m.Header[idx] = m.header[idx] + " " + arrow
+}
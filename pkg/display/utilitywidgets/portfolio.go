@@ -18,14 +18,12 @@ package utilitywidgets
 
 import (
 	"fmt"
-	"net/http"
 	"strings"
-	"sync"
 
+	"github.com/Gituser143/cryptgo/pkg/api"
 	"github.com/Gituser143/cryptgo/pkg/utils"
 	"github.com/Gituser143/cryptgo/pkg/widgets"
 	ui "github.com/gizak/termui/v3"
-	gecko "github.com/superoo7/go-gecko/v3"
 )
 
 // PortfolioTable holds a table which helps display a mini portfolio
@@ -43,6 +41,7 @@ func NewPortfolioPage() *PortfolioTable {
 	p.Table.Header = []string{"Coin", "Symbol", "Price", "Holding", "Balance"}
 	p.Table.CursorColor = ui.ColorCyan
 	p.Table.ShowCursor = true
+	p.Table.UniqueCol = 5
 	p.Table.ColWidths = []int{5, 5, 5, 5, 5}
 	p.Table.ColResizer = func() {
 		x := p.Table.Inner.Dx()
@@ -81,51 +80,35 @@ func (p *PortfolioTable) Draw(buf *ui.Buffer) {
 	p.Table.Draw(buf)
 }
 
-// UpdateRows data
-func (p *PortfolioTable) UpdateRows(portfolio map[string]float64, currency string, currencyVal float64) {
-
-	var wg sync.WaitGroup
-	var m sync.Mutex
-
-	client := &http.Client{}
-
-	geckoClient := gecko.NewClient(client)
-
+// UpdateRows sets coins held as rows in the table, with the amounts held in
+// portfolio. Prices of coins are divided by divisor to get them in currency.
+func (p *PortfolioTable) UpdateRows(portfolio map[string]float64, coins []api.CoinMarket, currency string, divisor float64) {
 	rows := [][]string{}
 	sum := 0.0
-	for coin, amt := range portfolio {
-		wg.Add(1)
-		go func(coin string, amt float64, wg *sync.WaitGroup, m *sync.Mutex) {
-			defer wg.Done()
-
-			data, err := geckoClient.CoinsID(coin, false, false, true, false, false, false)
-			if err != nil {
-				return
-			}
-
-			p := data.MarketData.CurrentPrice["usd"]
-
-			row := []string{
-				data.Name,
-				strings.ToUpper(data.Symbol),
-				fmt.Sprintf("%.2f", p/currencyVal),
-				fmt.Sprintf("%.6f", amt),
-				fmt.Sprintf("%.4f", p*amt/currencyVal),
-			}
-
-			m.Lock()
-			sum += p * amt / currencyVal
-			rows = append(rows, row)
-			m.Unlock()
-
-		}(coin, amt, &wg, &m)
-	}
 
-	wg.Wait()
+	for _, data := range coins {
+		amt, ok := portfolio[data.ID]
+		if !ok {
+			continue
+		}
+		price := data.CurrentPrice / divisor
+
+		row := []string{
+			data.Name,
+			strings.ToUpper(data.Symbol),
+			utils.FormatPrice(price),
+			fmt.Sprintf("%.6f", amt),
+			utils.FormatPrice(price * amt),
+			data.ID, // not displayed, identifies the coin
+		}
+
+		sum += price * amt
+		rows = append(rows, row)
+	}
 
 	p.Header[2] = fmt.Sprintf("Price (%s)", currency)
 	p.Header[4] = fmt.Sprintf("Balance (%s)", currency)
 	p.Rows = rows
-	p.Title = fmt.Sprintf(" Portfolio: %.4f %s ", sum, currency)
+	p.Title = fmt.Sprintf(" Portfolio: %s %s ", utils.FormatPrice(sum), currency)
 	utils.SortData(p.Rows, 4, false, "PORTFOLIO")
 }
@@ -0,0 +1,110 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utilitywidgets
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Gituser143/cryptgo/pkg/widgets"
+	ui "github.com/gizak/termui/v3"
+)
+
+// ScreensTable holds a table to pick one of the saved screener queries
+type ScreensTable struct {
+	*widgets.Table
+}
+
+// NewScreensPage creates, initialises and returns a pointer to an instance of ScreensTable
+func NewScreensPage() *ScreensTable {
+	s := &ScreensTable{
+		Table: widgets.NewTable(),
+	}
+
+	s.Table.Title = " Screens "
+	s.Table.Header = []string{"Name", "Query"}
+	s.Table.CursorColor = ui.ColorCyan
+	s.Table.ShowCursor = true
+	s.Table.ColWidths = []int{5, 5}
+	s.Table.ColResizer = func() {
+		x := s.Table.Inner.Dx()
+		s.Table.ColWidths = []int{
+			x / 4,
+			x - x/4,
+		}
+	}
+	return s
+}
+
+// Resize helps resize the ScreensTable according to terminal dimensions
+func (s *ScreensTable) Resize(termWidth, termHeight int) {
+	textWidth := 100
+
+	textHeight := len(s.Table.Rows) + 3
+	if textHeight < 6 {
+		textHeight = 6
+	}
+	x := (termWidth - textWidth) / 2
+	y := (termHeight - textHeight) / 2
+	if x < 0 {
+		x = 0
+		textWidth = termWidth
+	}
+	if y < 0 {
+		y = 0
+		textHeight = termHeight
+	}
+
+	s.Table.SetRect(x, y, textWidth+x, textHeight+y)
+}
+
+// Draw puts the required text into the widget
+func (s *ScreensTable) Draw(buf *ui.Buffer) {
+	s.Table.Draw(buf)
+}
+
+// UpdateRows lists screens, sorted by name
+func (s *ScreensTable) UpdateRows(screens map[string]string) {
+	names := []string{}
+	for name := range screens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := [][]string{}
+	for _, name := range names {
+		rows = append(rows, []string{name, screens[name]})
+	}
+
+	s.Table.Rows = rows
+	s.Table.Title = " Screens: n new, e edit, d delete, <Enter> apply "
+}
+
+// Selected returns the name and query of the screen under the cursor
+func (s *ScreensTable) Selected() (string, string, bool) {
+	if s.Table.SelectedRow < len(s.Table.Rows) {
+		row := s.Table.Rows[s.Table.SelectedRow]
+		return row[0], row[1], true
+	}
+
+	return "", "", false
+}
+
+// SetError shows an error met on a query in the title
+func (s *ScreensTable) SetError(err error) {
+	s.Table.Title = fmt.Sprintf(" Screens: %s ", err)
+}
@@ -25,4 +25,11 @@ const (
 	Change
 	Duration
 	Currency
+	Error
+	Pick
+	Markets
+	Activity
+	Info
+	Screens
+	Categories
 )
@@ -0,0 +1,538 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package screener parses and evaluates queries screening coins by their
+// market data, such as "mcap > 1e9 and change7d < -10 and volume/mcap > 0.05".
+//
+// Queries combine comparisons (<, <=, >, >=, ==, !=) of arithmetic
+// expressions (+, -, *, /) over fields and numbers with and, or, not and
+// parentheses. Numbers may be suffixed with K, M, B or T. A comparison
+// involving a field a coin has no value for, such as change1y for a coin
+// listed recently, is unknown, and so is its negation. Coins only match if
+// the query is true without the values they miss.
+package screener
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+	"github.com/Gituser143/cryptgo/pkg/utils"
+)
+
+// fields maps field names to getters of their value from a coin, with
+// prices, market caps and volumes divided by divisor
+var fields = map[string]func(coin api.CoinMarket, divisor float64) float64{
+	"price":     func(c api.CoinMarket, d float64) float64 { return c.CurrentPrice / d },
+	"mcap":      func(c api.CoinMarket, d float64) float64 { return c.MarketCap / d },
+	"volume":    func(c api.CoinMarket, d float64) float64 { return c.TotalVolume / d },
+	"high24h":   func(c api.CoinMarket, d float64) float64 { return c.High24 / d },
+	"low24h":    func(c api.CoinMarket, d float64) float64 { return c.Low24 / d },
+	"rank":      func(c api.CoinMarket, d float64) float64 { return positive(float64(c.MarketCapRank)) },
+	"supply":    func(c api.CoinMarket, d float64) float64 { return positive(c.CirculatingSupply) },
+	"maxsupply": func(c api.CoinMarket, d float64) float64 { return positive(c.MaxSupply) },
+}
+
+func init() {
+	for _, duration := range []string{"1h", "24h", "7d", "14d", "30d", "200d", "1y"} {
+		duration := duration
+		fields["change"+duration] = func(c api.CoinMarket, d float64) float64 {
+			if change, ok := c.PriceChangePercentage[duration]; ok {
+				return change
+			}
+			return math.NaN()
+		}
+	}
+}
+
+// positive returns n, or NaN if n is not positive as values such as the rank
+// of a coin are 0 when unknown
+func positive(n float64) float64 {
+	if n > 0 {
+		return n
+	}
+	return math.NaN()
+}
+
+// Fields returns the names of fields queries can use, sorted
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Query is a parsed screener query
+type Query struct {
+	text string
+	root node
+}
+
+// Parse parses a screener query
+func Parse(text string) (*Query, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+
+	return &Query{text: text, root: root}, nil
+}
+
+// String returns the text the query was parsed from
+func (q *Query) String() string {
+	return q.text
+}
+
+// Match reports whether coin passes the query, with prices, market caps and
+// volumes of the coin divided by divisor to compare them in a currency
+func (q *Query) Match(coin api.CoinMarket, divisor float64) bool {
+	return truthy(q.root.eval(coin, divisor))
+}
+
+// Screen returns the coins passing the query, in order
+func (q *Query) Screen(coins []api.CoinMarket, divisor float64) []api.CoinMarket {
+	matches := []api.CoinMarket{}
+	for _, coin := range coins {
+		if q.Match(coin, divisor) {
+			matches = append(matches, coin)
+		}
+	}
+	return matches
+}
+
+// truthy reports whether a value is true, values which are not numbers are
+// unknown and so not true
+func truthy(v float64) bool {
+	return v != 0 && !math.IsNaN(v)
+}
+
+// falsy reports whether a value is false, values which are not numbers are
+// unknown and so not false
+func falsy(v float64) bool {
+	return v == 0
+}
+
+// boolValue converts b to a value
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// node is a node of a parsed query
+type node interface {
+	eval(coin api.CoinMarket, divisor float64) float64
+}
+
+type numberNode float64
+
+func (n numberNode) eval(api.CoinMarket, float64) float64 {
+	return float64(n)
+}
+
+type fieldNode string
+
+func (f fieldNode) eval(coin api.CoinMarket, divisor float64) float64 {
+	return fields[string(f)](coin, divisor)
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) eval(coin api.CoinMarket, divisor float64) float64 {
+	v := n.operand.eval(coin, divisor)
+	if math.IsNaN(v) {
+		return v
+	}
+	return boolValue(falsy(v))
+}
+
+type negNode struct {
+	operand node
+}
+
+func (n negNode) eval(coin api.CoinMarket, divisor float64) float64 {
+	return -n.operand.eval(coin, divisor)
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (b binaryNode) eval(coin api.CoinMarket, divisor float64) float64 {
+	// Short circuit logical operators, which are unknown if an operand is
+	// and the other does not decide the result
+	switch b.op {
+	case "and":
+		x := b.left.eval(coin, divisor)
+		if falsy(x) {
+			return 0
+		}
+		y := b.right.eval(coin, divisor)
+		if falsy(y) {
+			return 0
+		}
+		if math.IsNaN(x) || math.IsNaN(y) {
+			return math.NaN()
+		}
+		return 1
+	case "or":
+		x := b.left.eval(coin, divisor)
+		if truthy(x) {
+			return 1
+		}
+		y := b.right.eval(coin, divisor)
+		if truthy(y) {
+			return 1
+		}
+		if math.IsNaN(x) || math.IsNaN(y) {
+			return math.NaN()
+		}
+		return 0
+	}
+
+	x := b.left.eval(coin, divisor)
+	y := b.right.eval(coin, divisor)
+
+	// Comparisons with unknown values are false
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return math.NaN()
+	}
+
+	switch b.op {
+	case "+":
+		return x + y
+	case "-":
+		return x - y
+	case "*":
+		return x * y
+	case "/":
+		if y == 0 {
+			return math.NaN()
+		}
+		return x / y
+	case "<":
+		return boolValue(x < y)
+	case "<=":
+		return boolValue(x <= y)
+	case ">":
+		return boolValue(x > y)
+	case ">=":
+		return boolValue(x >= y)
+	case "==":
+		return boolValue(x == y)
+	case "!=":
+		return boolValue(x != y)
+	}
+
+	return math.NaN()
+}
+
+// parser is a recursive descent parser of tokens, with precedence from
+// lowest to highest: or, and, not, comparisons, + and -, * and /, unary -
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// accept consumes the next token and returns true if it is one of ops
+func (p *parser) accept(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != opToken {
+		return "", false
+	}
+
+	for _, op := range ops {
+		if p.tokens[p.pos].text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{"or", left, right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{"and", left, right}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.accept("not"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	if op, ok := p.accept("<", "<=", ">", ">=", "==", "!="); ok {
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op, left, right}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op, left, right}
+	}
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op, left, right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+
+	if _, ok := p.accept("("); ok {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, fmt.Errorf("missing )")
+		}
+		return inner, nil
+	}
+
+	tok := p.tokens[p.pos]
+	switch tok.kind {
+	case numberToken:
+		p.pos++
+		return numberNode(tok.value), nil
+	case fieldToken:
+		p.pos++
+		return fieldNode(tok.text), nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+// tokenKind identifies the kind of a token
+type tokenKind int
+
+const (
+	opToken tokenKind = iota
+	numberToken
+	fieldToken
+)
+
+// token is a lexical token of a query
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+}
+
+// tokenize splits a query into tokens
+func tokenize(text string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			i++
+
+		case strings.ContainsRune("()+-*/", r):
+			tokens = append(tokens, token{kind: opToken, text: string(r)})
+			i++
+
+		case strings.ContainsRune("<>=!", r):
+			op := string(r)
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				op += "="
+				i++
+			}
+
+			switch op {
+			case "=":
+				op = "=="
+			case "!":
+				return nil, fmt.Errorf("unexpected \"!\", use not")
+			}
+			tokens = append(tokens, token{kind: opToken, text: op})
+
+		case r == '.' || (r >= '0' && r <= '9'):
+			start := i
+			for i < len(runes) && (runes[i] == '.' || (runes[i] >= '0' && runes[i] <= '9')) {
+				i++
+			}
+
+			// Exponent, such as 1e9 or 2.5e-3
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && runes[j] >= '0' && runes[j] <= '9' {
+					i = j
+					for i < len(runes) && runes[i] >= '0' && runes[i] <= '9' {
+						i++
+					}
+				}
+			}
+
+			// Unit suffix, such as 1.5B
+			if i < len(runes) && strings.ContainsRune("kKmMbBtT", runes[i]) &&
+				(i+1 == len(runes) || !isWordRune(runes[i+1])) {
+				i++
+			}
+
+			word := string(runes[start:i])
+			value, err := utils.ParseAmount(word)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", word)
+			}
+			tokens = append(tokens, token{kind: numberToken, text: word, value: value})
+
+		case isWordRune(r):
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+
+			word := strings.ToLower(string(runes[start:i]))
+			switch word {
+			case "and", "or", "not":
+				tokens = append(tokens, token{kind: opToken, text: word})
+			default:
+				if _, ok := fields[word]; !ok {
+					return nil, fmt.Errorf("unknown field %q, fields are: %s", word, strings.Join(Fields(), ", "))
+				}
+				tokens = append(tokens, token{kind: fieldToken, text: word})
+			}
+
+		default:
+			return nil, fmt.Errorf("unexpected %q", string(r))
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+
+	return tokens, nil
+}
+
+// isWordRune reports whether r may be part of a field name or keyword
+func isWordRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
@@ -0,0 +1,244 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package screener
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Gituser143/cryptgo/pkg/api"
+)
+
+// coins are sample coins queries are evaluated against
+var coins = []api.CoinMarket{
+	{
+		ID:                    "bitcoin",
+		MarketCapRank:         1,
+		CurrentPrice:          50000,
+		MarketCap:             1e12,
+		TotalVolume:           3e10,
+		CirculatingSupply:     19e6,
+		TotalSupply:           19e6,
+		MaxSupply:             21e6,
+		PriceChangePercentage: map[string]float64{"24h": 2, "7d": -5, "1y": 80},
+	},
+	{
+		ID:                    "ethereum",
+		MarketCapRank:         2,
+		CurrentPrice:          3000,
+		MarketCap:             3.6e11,
+		TotalVolume:           2e10,
+		CirculatingSupply:     120e6,
+		TotalSupply:           120e6,
+		PriceChangePercentage: map[string]float64{"24h": -1, "7d": -12, "1y": 40},
+	},
+	{
+		ID:                    "newcoin",
+		CurrentPrice:          0.5,
+		MarketCap:             5e6,
+		TotalVolume:           1e6,
+		PriceChangePercentage: map[string]float64{"24h": 30, "7d": 150},
+	},
+}
+
+// screen returns the IDs of coins passing query, priced in USD
+func screen(t *testing.T, query string) []string {
+	t.Helper()
+
+	q, err := Parse(query)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", query, err)
+	}
+
+	ids := []string{}
+	for _, coin := range q.Screen(coins, 1) {
+		ids = append(ids, coin.ID)
+	}
+	return ids
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{"", "empty query"},
+		{"   ", "empty query"},
+		{"mcap >", "unexpected end of query"},
+		{"(mcap > 1", "missing )"},
+		{"mcap > 1)", `unexpected ")"`},
+		{"marketcap > 1", `unknown field "marketcap"`},
+		{"mcap ! 1", `use not`},
+		{"mcap > 1 and", "unexpected end of query"},
+		{"mcap > 1..2", `invalid number "1..2"`},
+		{"mcap > 1 2", `unexpected "2"`},
+		{"mcap # 1", `unexpected "#"`},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		if err == nil {
+			t.Errorf("Parse(%q): expected error containing %q", tt.query, tt.err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%q): got error %q, want it to contain %q", tt.query, err, tt.err)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		query string
+		want  float64
+	}{
+		// Precedence of arithmetic
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"12 / 3 / 2", 2},
+		{"-2 * 3", -6},
+		{"- -2", 2},
+
+		// Comparisons bind looser than arithmetic
+		{"1 + 1 == 2", 1},
+		{"2 * 3 > 5 + 1", 0},
+		{"2 = 2", 1},
+		{"2 != 2", 0},
+
+		// not binds looser than comparisons, and tighter than and and or
+		{"not 1 > 2", 1},
+		{"not 1 < 2 and 1 < 2", 0},
+		{"1 > 2 and 1 > 2 or 1 < 2", 1},
+		{"1 < 2 or 1 < 2 and 1 > 2", 1},
+		{"(1 < 2 or 1 < 2) and 1 > 2", 0},
+
+		// Numbers with exponents and units
+		{"1e3", 1000},
+		{"2.5e-3", 0.0025},
+		{"1.5k", 1500},
+		{"2M", 2e6},
+		{"3b", 3e9},
+		{"1T", 1e12},
+		{".5", 0.5},
+
+		// Operators are case insensitive
+		{"1 < 2 AND NOT 1 > 2", 1},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.query, err)
+			continue
+		}
+
+		if got := q.root.eval(api.CoinMarket{}, 1); got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestUnknownValues(t *testing.T) {
+	tests := []struct {
+		query string
+		want  float64 // NaN if unknown
+	}{
+		{"change1y", math.NaN()},
+		{"change1y > 0", math.NaN()},
+		{"change1y + 1", math.NaN()},
+		{"rank", math.NaN()},
+		{"1 / 0", math.NaN()},
+		{"1 / 0 > 0", math.NaN()},
+
+		// Unknown values stay unknown unless the other operand decides
+		{"not change1y > 0", math.NaN()},
+		{"not not change1y > 0", math.NaN()},
+		{"change1y > 0 or 1 < 2", 1},
+		{"change1y > 0 or 1 > 2", math.NaN()},
+		{"change1y > 0 and 1 < 2", math.NaN()},
+		{"change1y > 0 and 1 > 2", 0},
+		{"not (change1y > 0 and 1 > 2)", 1},
+		{"change24h", 30},
+	}
+
+	newCoin := coins[2]
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.query, err)
+			continue
+		}
+
+		got := q.root.eval(newCoin, 1)
+		if math.IsNaN(tt.want) {
+			if !math.IsNaN(got) {
+				t.Errorf("%q = %v, want NaN", tt.query, got)
+			}
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestScreen(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"mcap > 1e9", []string{"bitcoin", "ethereum"}},
+		{"mcap > 500B", []string{"bitcoin"}},
+		{"mcap < 10m", []string{"newcoin"}},
+		{"change7d < -10", []string{"ethereum"}},
+		{"change7d < -10 or change24h > 10", []string{"ethereum", "newcoin"}},
+		{"volume / mcap > 0.05", []string{"ethereum", "newcoin"}},
+		{"change1y > 0", []string{"bitcoin", "ethereum"}},
+		{"not change1y > 0", []string{}},
+		{"not change1y > 100", []string{"bitcoin", "ethereum"}},
+		{"rank <= 2", []string{"bitcoin", "ethereum"}},
+		{"supply / maxsupply > 0.9", []string{"bitcoin"}},
+		{"not supply / maxsupply > 0.9", []string{}},
+		{"price > 1 and price < 10000", []string{"ethereum"}},
+		{"price < 0", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := screen(t, tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q matched %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMatchDivisor(t *testing.T) {
+	// Prices are compared in the currency they are divided into
+	q, err := Parse("price > 40000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bitcoin := coins[0]
+	if !q.Match(bitcoin, 1) {
+		t.Error("expected bitcoin to match in USD")
+	}
+	if q.Match(bitcoin, 2) {
+		t.Error("expected bitcoin not to match in a currency worth 2 USD")
+	}
+}
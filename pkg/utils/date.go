@@ -0,0 +1,59 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayouts lists layouts dates can be entered in, in local time
+var dateLayouts = []string{"2006-01-02", "2006-01-02 15:04"}
+
+// ParseDate parses a date such as "2021-05-01" or "2021-05-01 14:30", in
+// local time
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or YYYY-MM-DD HH:MM", s)
+}
+
+// FormatDate formats a date as it is entered, leaving out the time of day
+// at midnight
+func FormatDate(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return t.Format(dateLayouts[0])
+	}
+
+	return t.Format(dateLayouts[1])
+}
+
+// FormatDateRange formats the range between two dates, a zero end meaning
+// the range is open up to now
+func FormatDateRange(from, to time.Time) string {
+	if to.IsZero() {
+		return FormatDate(from) + " to now"
+	}
+
+	return FormatDate(from) + " to " + FormatDate(to)
+}
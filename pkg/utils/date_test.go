@@ -0,0 +1,93 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Time
+		err  bool
+	}{
+		{s: "2021-05-01", want: time.Date(2021, 5, 1, 0, 0, 0, 0, time.Local)},
+		{s: " 2021-05-01 ", want: time.Date(2021, 5, 1, 0, 0, 0, 0, time.Local)},
+		{s: "2021-05-01 14:30", want: time.Date(2021, 5, 1, 14, 30, 0, 0, time.Local)},
+		{s: "", err: true},
+		{s: "2021-5-1", err: true},
+		{s: "01/05/2021", err: true},
+		{s: "2021-02-30", err: true},
+		{s: "2021-05-01 25:00", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDate(tt.s)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseDate(%q) = %v, want error", tt.s, got)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseDate(%q): %v", tt.s, err)
+		} else if !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2021, 5, 1, 0, 0, 0, 0, time.Local), "2021-05-01"},
+		{time.Date(2021, 5, 1, 14, 30, 0, 0, time.Local), "2021-05-01 14:30"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDate(tt.t); got != tt.want {
+			t.Errorf("FormatDate(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+
+		// Formatted dates are parsed back
+		if parsed, err := ParseDate(tt.want); err != nil || !parsed.Equal(tt.t) {
+			t.Errorf("ParseDate(%q) = %v, %v, want %v", tt.want, parsed, err, tt.t)
+		}
+	}
+}
+
+func TestFormatDateRange(t *testing.T) {
+	from := time.Date(2021, 5, 1, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		to   time.Time
+		want string
+	}{
+		{time.Date(2021, 6, 1, 12, 0, 0, 0, time.Local), "2021-05-01 to 2021-06-01 12:00"},
+		{time.Time{}, "2021-05-01 to now"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDateRange(from, tt.to); got != tt.want {
+			t.Errorf("FormatDateRange(%v, %v) = %q, want %q", from, tt.to, got, tt.want)
+		}
+	}
+}
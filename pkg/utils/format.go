@@ -0,0 +1,46 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"math"
+	"strconv"
+)
+
+// priceDigits is the least number of significant digits prices are shown with
+const priceDigits = 4
+
+// maxPriceDecimals caps the decimals prices are shown with
+const maxPriceDecimals = 12
+
+// FormatPrice formats a price with at least 2 decimals, and more for small
+// prices so they keep priceDigits significant digits. This keeps prices
+// quoted in units such as BTC meaningful, where %.2f would print 0.00.
+func FormatPrice(price float64) string {
+	decimals := 2
+	if price != 0 && !math.IsInf(price, 0) && !math.IsNaN(price) {
+		magnitude := int(math.Floor(math.Log10(math.Abs(price))))
+		if d := priceDigits - 1 - magnitude; d > decimals {
+			decimals = d
+		}
+		if decimals > maxPriceDecimals {
+			decimals = maxPriceDecimals
+		}
+	}
+
+	return strconv.FormatFloat(price, 'f', decimals, 64)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		price float64
+		want  string
+	}{
+		{0, "0.00"},
+		{1234.5678, "1234.57"},
+		{50000, "50000.00"},
+		{-12.345, "-12.35"},
+
+		// Small prices keep 4 significant digits
+		{1, "1.000"},
+		{0.5, "0.5000"},
+		{0.012345, "0.01235"},
+		{0.00001234, "0.00001234"},
+		{-0.00001234, "-0.00001234"},
+
+		// Tiny prices are capped at 12 decimals
+		{1e-15, "0.000000000000"},
+
+		// Values which are not numbers keep 2 decimals
+		{math.Inf(1), "+Inf"},
+		{math.NaN(), "NaN"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatPrice(tt.price); got != tt.want {
+			t.Errorf("FormatPrice(%v) = %q, want %q", tt.price, got, tt.want)
+		}
+	}
+}
@@ -26,6 +26,14 @@ type Metadata struct {
 	Favourites map[string]bool    `json:"favourites"`
 	Currency   string             `json:"currency"`
 	Portfolio  map[string]float64 `json:"portfolio"`
+	Screens    map[string]string  `json:"screens,omitempty"` // Screener queries by name
+	Category   *SavedCategory     `json:"category,omitempty"`
+}
+
+// SavedCategory holds the category of coins selected on the main page
+type SavedCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 // Currency holds currency data when fetched from CoinCap
@@ -146,9 +154,88 @@ func GetCurrencyID() string {
 	return metadata.Currency
 }
 
-// SaveMetadata exports favourites, currency and portfolio to disk.
-// Data is saved on ~/.cryptgo-data.json
+// GetScreens reads stored screener queries from ~/.cryptgo-data.json and
+// returns them by name
+func GetScreens() map[string]string {
+	metadata := readMetadata()
+
+	if len(metadata.Screens) > 0 {
+		return metadata.Screens
+	}
+
+	return map[string]string{}
+}
+
+// SaveScreens exports screener queries, by name, to disk, keeping other
+// metadata
+func SaveScreens(screens map[string]string) error {
+	metadata := readMetadata()
+	metadata.Screens = screens
+
+	return writeMetadata(metadata)
+}
+
+// GetCategory reads the ID and name of the category of coins selected from
+// ~/.cryptgo-data.json, they are empty if all coins are selected
+func GetCategory() (string, string) {
+	metadata := readMetadata()
+
+	if metadata.Category == nil {
+		return "", ""
+	}
+
+	return metadata.Category.ID, metadata.Category.Name
+}
+
+// SaveCategory exports the ID and name of the category of coins selected to
+// disk, keeping other metadata. An empty ID selects all coins.
+func SaveCategory(id, name string) error {
+	metadata := readMetadata()
+	metadata.Category = nil
+	if id != "" {
+		metadata.Category = &SavedCategory{ID: id, Name: name}
+	}
+
+	return writeMetadata(metadata)
+}
+
+// SaveMetadata exports favourites, currency and portfolio to disk, keeping
+// other metadata. Data is saved on ~/.cryptgo-data.json
 func SaveMetadata(favourites map[string]bool, currency string, portfolio map[string]float64) error {
+	metadata := readMetadata()
+	metadata.Favourites = favourites
+	metadata.Currency = currency
+	metadata.Portfolio = portfolio
+
+	return writeMetadata(metadata)
+}
+
+// readMetadata reads metadata stored on ~/.cryptgo-data.json, metadata
+// which cannot be read is empty
+func readMetadata() Metadata {
+	metadata := Metadata{}
+
+	// Get home directory
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return metadata
+	}
+
+	// Open file
+	configFile, err := os.Open(homeDir + "/.cryptgo-data.json")
+	if err != nil {
+		return metadata
+	}
+	defer configFile.Close()
+
+	// Read content, keeping fields read before any error
+	json.NewDecoder(configFile).Decode(&metadata)
+
+	return metadata
+}
+
+// writeMetadata saves metadata on ~/.cryptgo-data.json
+func writeMetadata(metadata Metadata) error {
 	// Get Home directory
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -161,13 +248,6 @@ func SaveMetadata(favourites map[string]bool, currency string, portfolio map[str
 	configPath := homeDir + "/cryptgo-data.json"
 	hiddenPath := homeDir + "/.cryptgo-data.json"
 
-	// Create data
-	metadata := Metadata{
-		Favourites: favourites,
-		Currency:   currency,
-		Portfolio:  portfolio,
-	}
-
 	data, err := json.MarshalIndent(metadata, "", "\t")
 	if err != nil {
 		return err
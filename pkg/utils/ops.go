@@ -16,6 +16,8 @@ limitations under the License.
 
 package utils
 
+import "sort"
+
 // MinFloat64 returns minimum float from a given number of floats
 func MinFloat64(a ...float64) float64 {
 	var min float64
@@ -48,3 +50,44 @@ func MaxFloat64(a ...float64) float64 {
 	}
 	return max
 }
+
+// Median returns the median of the given floats, 0 if none are given
+func Median(a ...float64) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64{}, a...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// Resample averages consecutive values into at most n buckets of about equal
+// size, so a long series can be drawn in n columns
+func Resample(values []float64, n int) []float64 {
+	if n <= 0 {
+		return []float64{}
+	}
+	if len(values) <= n {
+		return append([]float64{}, values...)
+	}
+
+	buckets := make([]float64, n)
+	for i := range buckets {
+		start := i * len(values) / n
+		end := (i + 1) * len(values) / n
+
+		sum := 0.0
+		for _, val := range values[start:end] {
+			sum += val
+		}
+		buckets[i] = sum / float64(end-start)
+	}
+
+	return buckets
+}
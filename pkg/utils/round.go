@@ -16,7 +16,12 @@ limitations under the License.
 
 package utils
 
-import "math"
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
 
 var (
 	kilo = math.Pow(10, 3)
@@ -75,3 +80,38 @@ func RoundValues(num1, num2 float64) ([]float64, string) {
 
 	return nums, units
 }
+
+// FormatAmount formats a large amount, such as a market cap, with the units
+// of RoundValues, such as "1.50B". ParseAmount reads it back.
+func FormatAmount(amount float64) string {
+	vals, units := RoundValues(amount, 0)
+	return fmt.Sprintf("%.2f%s", vals[0], units)
+}
+
+// ParseAmount parses a number which may be suffixed with the units used by
+// RoundValues, such as "1.5B" or "200 M"
+func ParseAmount(s string) (float64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	multipliers := map[string]float64{
+		"K": kilo,
+		"M": mega,
+		"B": giga,
+		"T": tera,
+	}
+
+	multiplier := 1.0
+	if len(s) > 0 {
+		if m, ok := multipliers[s[len(s)-1:]]; ok {
+			multiplier = m
+			s = strings.TrimSpace(s[:len(s)-1])
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return n * multiplier, nil
+}
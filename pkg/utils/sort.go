@@ -79,6 +79,17 @@ func SortData(data [][]string, sortIdx int, sortAsc bool, sortCase string) {
 		return x > y
 	}
 
+	// trustSort orders trust levels, such as "High (stale)", by rank
+	trustSort := func(i, j int) bool {
+		ranks := map[string]int{"Low": 1, "Medium": 2, "High": 3}
+		x := ranks[strings.SplitN(data[i][sortIdx], " ", 2)[0]]
+		y := ranks[strings.SplitN(data[j][sortIdx], " ", 2)[0]]
+		if sortAsc {
+			return x < y
+		}
+		return x > y
+	}
+
 	// Set function map
 	sortFuncs := make(map[int]func(i, j int) bool)
 	switch sortCase {
@@ -107,6 +118,17 @@ func SortData(data [][]string, sortIdx int, sortAsc bool, sortCase string) {
 			6: floatSort,  // Holding %
 		}
 
+	case "MARKETS":
+		sortFuncs = map[int]func(i, j int) bool{
+			0: strSort,   // Exchange
+			1: strSort,   // Pair
+			2: floatSort, // Last
+			3: floatSort, // Volume
+			4: floatSort, // Spread %
+			5: floatSort, // Dev %
+			6: trustSort, // Trust
+		}
+
 	default:
 		sortFuncs[sortIdx] = strSort
 	}
@@ -17,33 +17,24 @@ limitations under the License.
 package utils
 
 import (
-	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
-// LoopTick runs a given action in a loop in periods of 't' duration. It exits
-// when the context is cancelled
-func LoopTick(ctx context.Context, t time.Duration, action func(errChan chan error)) error {
-	ticker := time.NewTicker(t)
-	defer ticker.Stop()
-
-	errChan := make(chan error)
-
-	for {
-
-		// Run action
-		go action(errChan)
-
-		select {
-		// Return if context is cancelled
-		case <-ctx.Done():
-			return ctx.Err()
-		case err := <-errChan:
-			if err != nil {
-				return err
-			}
-		// Break select every tick
-		case <-ticker.C:
-		}
+// staleMarker prefixes the time in titles of widgets showing stale data
+const staleMarker = "(stale since "
+
+// StaleTitle marks a widget title as showing data which is stale since the
+// given time. Any previous marker is replaced and a zero time removes it.
+func StaleTitle(title string, since time.Time) string {
+	if i := strings.Index(title, staleMarker); i != -1 {
+		title = title[:i]
+	}
+
+	if since.IsZero() {
+		return title
 	}
+
+	return fmt.Sprintf("%s%s%s) ", title, staleMarker, since.Format("15:04:05"))
 }
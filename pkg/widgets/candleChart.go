@@ -0,0 +1,171 @@
+/*
+Copyright © 2021 Bhargav SNV bhargavsnv100@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package widgets
+
+import (
+	"image"
+	"math"
+	"sort"
+
+	ui "github.com/gizak/termui/v3"
+)
+
+// Candle holds the open, high, low and close values over a period
+type Candle struct {
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+}
+
+// CandleChart implements a candlestick chart. The latest candle is drawn on
+// the right, and neighbouring candles are merged when there are more than
+// fit the width of the chart.
+type CandleChart struct {
+	*ui.Block
+
+	Data        []Candle
+	Labels      map[string]string
+	LabelColors map[string]ui.Color
+
+	UpColor   ui.Color
+	DownColor ui.Color
+}
+
+// NewCandleChart creates and returns a CandleChart instance
+func NewCandleChart() *CandleChart {
+	return &CandleChart{
+		Block: ui.NewBlock(),
+
+		Labels:      make(map[string]string),
+		LabelColors: make(map[string]ui.Color),
+
+		UpColor:   ui.ColorGreen,
+		DownColor: ui.ColorRed,
+	}
+}
+
+// Draw draws the CandleChart onto the UI
+func (c *CandleChart) Draw(buf *ui.Buffer) {
+	c.Block.Draw(buf)
+
+	width, height := c.Inner.Dx(), c.Inner.Dy()
+	if width > 0 && height > 0 && len(c.Data) > 0 {
+		c.drawCandles(buf, width, height)
+	}
+
+	// renders key/label ontop, but let candles be drawn over space characters
+	labels := make([]string, 0, len(c.Labels))
+	for name := range c.Labels {
+		labels = append(labels, name)
+	}
+	sort.Strings(labels)
+
+	for i, name := range labels {
+		if i+2 > height {
+			continue
+		}
+
+		str := name + " " + c.Labels[name]
+		for k, char := range str {
+			if char != ' ' {
+				buf.SetCell(
+					ui.NewCell(char, ui.NewStyle(c.LabelColors[name])),
+					image.Pt(c.Inner.Min.X+2+k, c.Inner.Min.Y+i+1),
+				)
+			}
+		}
+	}
+}
+
+// drawCandles draws candles with their wicks, spaced apart if there is room
+func (c *CandleChart) drawCandles(buf *ui.Buffer, width, height int) {
+	candles := mergeCandles(c.Data, width)
+
+	step := 1
+	body := '┃'
+	if len(candles)*2 <= width {
+		step = 2
+		body = '█'
+	}
+
+	// Scale values to rows
+	min, max := candles[0].Low, candles[0].High
+	for _, candle := range candles {
+		min = math.Min(min, candle.Low)
+		max = math.Max(max, candle.High)
+	}
+	row := func(v float64) int {
+		if max == min {
+			return height / 2
+		}
+		return int(math.Round((max - v) / (max - min) * float64(height-1)))
+	}
+
+	for i, candle := range candles {
+		x := c.Inner.Max.X - 1 - (len(candles)-1-i)*step
+
+		color := c.UpColor
+		if candle.Close < candle.Open {
+			color = c.DownColor
+		}
+
+		bodyTop := row(math.Max(candle.Open, candle.Close))
+		bodyBottom := row(math.Min(candle.Open, candle.Close))
+
+		for y := row(candle.High); y <= row(candle.Low); y++ {
+			char := '│'
+			if y >= bodyTop && y <= bodyBottom {
+				char = body
+			}
+			buf.SetCell(ui.NewCell(char, ui.NewStyle(color)), image.Pt(x, c.Inner.Min.Y+y))
+		}
+	}
+}
+
+// mergeCandles merges neighbouring candles so at most n remain. Groups are
+// counted from the latest candle, so only the oldest may be partial.
+func mergeCandles(candles []Candle, n int) []Candle {
+	if len(candles) <= n {
+		return candles
+	}
+
+	size := (len(candles) + n - 1) / n
+	merged := make([]Candle, (len(candles)+size-1)/size)
+
+	for i, end := len(merged)-1, len(candles); i >= 0; i, end = i-1, end-size {
+		start := end - size
+		if start < 0 {
+			start = 0
+		}
+
+		group := candles[start:end]
+		candle := Candle{
+			Open:  group[0].Open,
+			High:  group[0].High,
+			Low:   group[0].Low,
+			Close: group[len(group)-1].Close,
+		}
+		for _, val := range group[1:] {
+			candle.High = math.Max(candle.High, val.High)
+			candle.Low = math.Min(candle.Low, val.Low)
+		}
+		merged[i] = candle
+	}
+
+	return merged
+}
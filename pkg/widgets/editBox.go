@@ -253,42 +253,41 @@ var editBox EditBox
 
 const editBoxWidth = 30
 
-func redrawAll(symbol string) {
+func redrawAll(title string, width int) {
 	const coldef = termbox.ColorDefault
 	termbox.Clear(coldef, coldef)
 	w, h := termbox.Size()
+	if width > w-2 {
+		width = w - 2
+	}
 
 	midy := h / 2
-	midx := (w - editBoxWidth) / 2
+	midx := (w - width) / 2
 
 	// unicode box drawing chars around the edit box
 	if runewidth.EastAsianWidth {
 		termbox.SetCell(midx-1, midy, '|', coldef, coldef)
-		termbox.SetCell(midx+editBoxWidth, midy, '|', coldef, coldef)
+		termbox.SetCell(midx+width, midy, '|', coldef, coldef)
 		termbox.SetCell(midx-1, midy-1, '+', coldef, coldef)
 		termbox.SetCell(midx-1, midy+1, '+', coldef, coldef)
-		termbox.SetCell(midx+editBoxWidth, midy-1, '+', coldef, coldef)
-		termbox.SetCell(midx+editBoxWidth, midy+1, '+', coldef, coldef)
-		fill(midx, midy-1, editBoxWidth, 1, termbox.Cell{Ch: '-'})
-		fill(midx, midy+1, editBoxWidth, 1, termbox.Cell{Ch: '-'})
+		termbox.SetCell(midx+width, midy-1, '+', coldef, coldef)
+		termbox.SetCell(midx+width, midy+1, '+', coldef, coldef)
+		fill(midx, midy-1, width, 1, termbox.Cell{Ch: '-'})
+		fill(midx, midy+1, width, 1, termbox.Cell{Ch: '-'})
 	} else {
 		termbox.SetCell(midx-1, midy, '│', coldef, coldef)
-		termbox.SetCell(midx+editBoxWidth, midy, '│', coldef, coldef)
+		termbox.SetCell(midx+width, midy, '│', coldef, coldef)
 		termbox.SetCell(midx-1, midy-1, '┌', coldef, coldef)
 		termbox.SetCell(midx-1, midy+1, '└', coldef, coldef)
-		termbox.SetCell(midx+editBoxWidth, midy-1, '┐', coldef, coldef)
-		termbox.SetCell(midx+editBoxWidth, midy+1, '┘', coldef, coldef)
-		fill(midx, midy-1, editBoxWidth, 1, termbox.Cell{Ch: '─'})
-		fill(midx, midy+1, editBoxWidth, 1, termbox.Cell{Ch: '─'})
+		termbox.SetCell(midx+width, midy-1, '┐', coldef, coldef)
+		termbox.SetCell(midx+width, midy+1, '┘', coldef, coldef)
+		fill(midx, midy-1, width, 1, termbox.Cell{Ch: '─'})
+		fill(midx, midy+1, width, 1, termbox.Cell{Ch: '─'})
 	}
 
-	editBox.Draw(midx, midy, editBoxWidth, 1)
+	editBox.Draw(midx, midy, width, 1)
 	termbox.SetCursor(midx+editBox.cursorX(), midy)
 
-	title := " Enter Symbol/Name of coin "
-	if symbol != "" {
-		title = fmt.Sprintf(" Enter Amount in %s ", symbol)
-	}
 	tbprint(midx, midy-1, coldef, coldef, title)
 	tbprint(midx, midy+2, coldef, coldef, "ESC to Close")
 	tbprint(midx, midy+3, coldef, coldef, "Enter to Save")
@@ -308,9 +307,35 @@ func init() {
 
 // DrawEdit draws an editbox and returns input passed to the box
 func DrawEdit(ev <-chan ui.Event, symbol string) string {
+	title := " Enter Symbol/Name of coin "
+	if symbol != "" {
+		title = fmt.Sprintf(" Enter Amount in %s ", symbol)
+	}
+
+	return drawEdit(ev, title, editBoxWidth)
+}
+
+// DrawPrompt draws a wide editbox titled title, holding text to be edited,
+// and returns input passed to the box. Text held for DrawEdit is kept as it
+// was.
+func DrawPrompt(ev <-chan ui.Event, title, text string) string {
+	saved := editBox
+	defer func() { editBox = saved }()
+
+	editBox = EditBox{}
+	for _, r := range text {
+		editBox.insertRune(r)
+	}
+
+	return drawEdit(ev, " "+title+" ", 2*editBoxWidth)
+}
+
+// drawEdit draws an editbox of the given width and returns input passed to
+// the box
+func drawEdit(ev <-chan ui.Event, title string, width int) string {
 	termbox.SetInputMode(termbox.InputEsc)
 
-	redrawAll(symbol)
+	redrawAll(title, width)
 	defer termbox.HideCursor()
 	for {
 		for e := range ev {
@@ -342,7 +367,7 @@ func DrawEdit(ev <-chan ui.Event, symbol string) string {
 					editBox.insertRune([]rune(e.ID)[0])
 				}
 			}
-			redrawAll(symbol)
+			redrawAll(title, width)
 		}
 	}
 }
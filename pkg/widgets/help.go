@@ -34,6 +34,7 @@ var allKeybindings = [][]string{
 	{"  - G and <End>: jump to bottom"},
 	{"  - f: focus favourites table"},
 	{"  - F: focus coin table"},
+	{"  - T: focus trending table"},
 	{""},
 	{"Searching/Filtering"},
 	{"  - /: Open search box"},
@@ -49,10 +50,18 @@ var allKeybindings = [][]string{
 	{"  - C: Select Currency (from full list)"},
 	{"  - e: Add/Edit coin to Portfolio"},
 	{"  - P: View portfolio"},
+	{"  - a: Add coin to Portfolio by symbol (in portfolio)"},
 	{"  - s: Star, save to favourites"},
 	{"  - S: UnStar,remove from favourites"},
 	{"  - <Enter>: View Coin Information"},
 	{"  - %: Select Duration for Percentage Change"},
+	{"  - O: View global market overview"},
+	{"  - M: View top gainers and losers"},
+	{"  - L: Search all coins"},
+	{"  - V: Compare coins by symbol"},
+	{"  - t: Browse coins by category"},
+	{"  - x: Open screener"},
+	{"  - E: Show last error"},
 	{""},
 	{"To close this prompt: <Esc>"},
 }
@@ -78,6 +87,66 @@ var coinKeybindings = [][]string{
 	{"  - Use <F-column number> to sort descending."},
 	{"  - Eg: 1 to sort ascending on 1st Col and F1 for descending"},
 	{""},
+	{"Actions"},
+	{"  - r: Show history between dates"},
+	{"  - v: Toggle line/candle view"},
+	{"  - m: Toggle market cap overlay"},
+	{"  - M: Show markets (sortable)"},
+	{"  - a: Show community and developer activity"},
+	{"  - i: Show description and links"},
+	{"  - o or <Enter>: Open selected link (in info)"},
+	{"  - y: Copy selected link or address (in info)"},
+	{"  - E: Show last error"},
+	{""},
+	{"To close this prompt: <Esc>"},
+}
+
+var overviewKeybindings = [][]string{
+	{"Quit: q or <C-c>"},
+	{""},
+	{"Actions"},
+	{"  - E: Show last error"},
+	{""},
+	{"To close this prompt: <Esc>"},
+}
+
+var moversKeybindings = [][]string{
+	{"Quit: q or <C-c>"},
+	{""},
+	{"Table Navigation"},
+	{"  - k and <Up>: up"},
+	{"  - j and <Down>: down"},
+	{"  - <C-u>: half page up"},
+	{"  - <C-d>: half page down"},
+	{"  - <C-b>: full page up"},
+	{"  - <C-f>: full page down"},
+	{"  - gg and <Home>: jump to top"},
+	{"  - G and <End>: jump to bottom"},
+	{"  - h and <Left>: focus gainers table"},
+	{"  - l and <Right>: focus losers table"},
+	{"  - <Tab>: focus tables of the next duration"},
+	{""},
+	{"Actions"},
+	{"  - m: Set minimum market cap, such as 1B"},
+	{"  - v: Set minimum volume, such as 10M"},
+	{"  - <Enter>: View Coin Information"},
+	{"  - E: Show last error"},
+	{""},
+	{"To close this prompt: <Esc>"},
+}
+
+var compareKeybindings = [][]string{
+	{"Quit: q or <C-c>"},
+	{""},
+	{"Table Navigation"},
+	{"  - k and <Up>: up"},
+	{"  - j and <Down>: down"},
+	{"  - gg and <Home>: jump to top"},
+	{"  - G and <End>: jump to bottom"},
+	{""},
+	{"Actions"},
+	{"  - d: Change Interval Duration"},
+	{"  - E: Show last error"},
 	{""},
 	{"To close this prompt: <Esc>"},
 }
@@ -105,6 +174,7 @@ var portfolioKeybindings = [][]string{
 	{"  - C: Select Currency (from full list)"},
 	{"  - e: Add/Edit coin to Portfolio"},
 	{"  - <Enter>: View Coin Information"},
+	{"  - E: Show last error"},
 	{""},
 	{"To close this prompt: <Esc>"},
 }
@@ -172,5 +242,11 @@ func (help *HelpMenu) SelectHelpMenu(page string) {
 		help.Keybindings = coinKeybindings
 	case "PORTFOLIO":
 		help.Keybindings = portfolioKeybindings
+	case "OVERVIEW":
+		help.Keybindings = overviewKeybindings
+	case "MOVERS":
+		help.Keybindings = moversKeybindings
+	case "COMPARE":
+		help.Keybindings = compareKeybindings
 	}
 }